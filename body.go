@@ -0,0 +1,81 @@
+package validapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+//ValidateBody returns net/http middleware that validates the request body against the
+// PropertyGroup before calling the next handler. it can be used with http.ServeMux or any
+// other router. invalid requests receive a 400 response written by ProblemRenderer.
+func ValidateBody(pg *PropertyGroup) func(http.Handler) http.Handler {
+	return ValidateBodyWith(pg, ProblemRenderer)
+}
+
+//ValidateBodyWith works like ValidateBody, but writes failures with the ErrorRenderer.
+// the decoded body is available to the next handler through BodyFromRequest, and the
+// request body is restored so it can be decoded again.
+func ValidateBodyWith(pg *PropertyGroup, render ErrorRenderer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r, err := validateRequestBody(r, pg)
+			if err != nil {
+				render(w, r, http.StatusBadRequest, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+//BodyFromRequest returns the body decoded while validating the request, with numbers as
+// json.Number. returns nil if the body was not validated.
+func BodyFromRequest(r *http.Request) map[string]interface{} {
+	body, _ := r.Context().Value(bodyKey).(map[string]interface{})
+	return body
+}
+
+//BodyAs decodes the body that was validated for the request into v, which must be a pointer.
+// keys are matched to fields the same way encoding/json does it, so fields should be named
+// or tagged after the properties of the PropertyGroup. the request body is not read again,
+// so v always holds the values that were validated. returns ErrBodyNotValidated if the body
+// was not validated by a route or ValidateBody.
+func BodyAs(r *http.Request, v interface{}) error {
+	body := BodyFromRequest(r)
+	if body == nil {
+		return ErrBodyNotValidated
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+//validateRequestBody reads the request body and validates it against the group. it returns
+// a copy of the request with the decoded body stored in its context and the body restored.
+func validateRequestBody(r *http.Request, pg *PropertyGroup) (*http.Request, error) {
+	raw, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return r, errors.New("could not read request body")
+	}
+	body, err := pg.ValidateJSON(raw)
+	if err != nil {
+		if err == ErrNotJSONObject {
+			err = errors.New("request body must be a json object")
+		}
+		return r, err
+	}
+	r = withBody(r, body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(raw))
+	return r, nil
+}
+
+//withBody returns a shallow copy of the request with the body stored in its context.
+func withBody(r *http.Request, body map[string]interface{}) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), bodyKey, body))
+}
@@ -0,0 +1,122 @@
+package validapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateBody(t *testing.T) {
+	group := NewPropertyGroup().AddProperties(NewProperty("Name", String).Required())
+	var body map[string]interface{}
+	var raw string
+	handler := ValidateBody(group)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = BodyFromRequest(r)
+		b, _ := ioutil.ReadAll(r.Body)
+		raw = string(b)
+	}))
+
+	testData := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"valid body", `{"Name": "Jimbo"}`, http.StatusOK},
+		{"invalid body", `{"Name": 12}`, http.StatusBadRequest},
+		{"not json", `Name`, http.StatusBadRequest},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			body, raw = nil, ""
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(i.body)))
+			if rec.Code != i.want {
+				t.Errorf("got status %v want %v", rec.Code, i.want)
+			}
+			if i.want != http.StatusOK {
+				if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+					t.Errorf("got content type %v want application/problem+json", ct)
+				}
+				return
+			}
+			if body["Name"] != "Jimbo" {
+				t.Errorf("body not in context. got %v", body)
+			}
+			if raw != i.body {
+				t.Errorf("body not restored. got %q", raw)
+			}
+		})
+	}
+}
+
+func TestValidateBodyWith(t *testing.T) {
+	group := NewPropertyGroup().AddProperties(NewProperty("Name", String))
+	render := func(w http.ResponseWriter, r *http.Request, status int, err error) {
+		w.WriteHeader(http.StatusTeapot)
+		json.NewEncoder(w).Encode(err)
+	}
+	handler := ValidateBodyWith(group, render)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"ID": 1}`)))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("got status %v want %v", rec.Code, http.StatusTeapot)
+	}
+}
+
+func TestBodyFromRequest(t *testing.T) {
+	api := New()
+	var body map[string]interface{}
+	api.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		body = BodyFromRequest(r)
+	}).Body(NewPropertyGroup().AddProperties(NewProperty("ID", Int)))
+
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"ID": 12}`)))
+	if body["ID"] != json.Number("12") {
+		t.Errorf("got %v want 12", body["ID"])
+	}
+	if got := BodyFromRequest(httptest.NewRequest(http.MethodGet, "/", nil)); got != nil {
+		t.Errorf("got %v want nil", got)
+	}
+}
+
+func TestBodyAs(t *testing.T) {
+	type user struct {
+		Name  string   `json:"name"`
+		ID    int64    `json:"id"`
+		Score float64  `json:"score"`
+		Tags  []string `json:"tags"`
+	}
+	group := NewPropertyGroup().AddProperties(
+		NewProperty("name", String),
+		NewProperty("id", Int64),
+		NewProperty("score", Float),
+		NewArrayProperty("tags", String),
+	)
+
+	var got user
+	var err error
+	handler := ValidateBody(group)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the handler reading the body must not change what BodyAs returns.
+		ioutil.ReadAll(r.Body)
+		err = BodyAs(r, &got)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users",
+		strings.NewReader(`{"name": "Jimbo", "id": 9007199254740993, "score": 1.5, "tags": ["a"]}`)))
+
+	if err != nil {
+		t.Fatalf("wanted nil got %v", err.Error())
+	}
+	want := user{Name: "Jimbo", ID: 9007199254740993, Score: 1.5, Tags: []string{"a"}}
+	if got.Name != want.Name || got.ID != want.ID || got.Score != want.Score || len(got.Tags) != 1 {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	if err := BodyAs(httptest.NewRequest(http.MethodGet, "/", nil), &got); err != ErrBodyNotValidated {
+		t.Errorf("wanted ErrBodyNotValidated got %v", err)
+	}
+}
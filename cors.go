@@ -0,0 +1,167 @@
+package validapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//CORSPolicy describes which cross origin requests are allowed and the headers used to
+// answer them. it is set on a Router, and the policy of the innermost Router on the branch
+// that serves the request is used, so a mounted Router can override the policy of its parent.
+type CORSPolicy struct {
+	//AllowedOrigins lists the origins that may make requests. entries can be an exact
+	// origin, "*" for any origin, or contain a single wildcard such as "https://*.example.com"
+	// to allow any subdomain.
+	AllowedOrigins []string
+	//AllowOriginFunc is called for origins that are not in AllowedOrigins. the origin is
+	// allowed if it returns true.
+	AllowOriginFunc func(origin string) bool
+	//AllowedMethods limits the methods allowed in preflight requests. if empty, the methods
+	// registered for the path are allowed.
+	AllowedMethods []string
+	//AllowedHeaders lists the request headers clients may send. "*" allows any header.
+	AllowedHeaders []string
+	//ExposedHeaders lists the response headers the client may read.
+	ExposedHeaders []string
+	//AllowCredentials allows requests with cookies or authorization headers. browsers do
+	// not allow credentials with "*", so when set, a "*" entry in AllowedOrigins is ignored
+	// and origins must be listed or allowed by AllowOriginFunc. the origin is echoed back.
+	AllowCredentials bool
+	//MaxAge is the number of seconds the result of a preflight request may be cached.
+	// it is not sent when zero.
+	MaxAge int
+}
+
+//allowOrigin reports if the origin is allowed by the policy.
+func (c *CORSPolicy) allowOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			if !c.AllowCredentials {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if i := strings.IndexByte(allowed, '*'); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+	return c.AllowOriginFunc != nil && c.AllowOriginFunc(origin)
+}
+
+//allowAnyOrigin reports if the policy allows every origin with "*".
+func (c *CORSPolicy) allowAnyOrigin() bool {
+	return containsString(c.AllowedOrigins, "*")
+}
+
+//allowHeaders reports if all of the requested headers are allowed by the policy.
+func (c *CORSPolicy) allowHeaders(requested []string) bool {
+	if containsString(c.AllowedHeaders, "*") {
+		return true
+	}
+	for _, h := range requested {
+		found := false
+		for _, allowed := range c.AllowedHeaders {
+			if strings.EqualFold(h, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+//setOrigin sets the headers shared by preflight and actual responses. returns false if the
+// origin is not allowed, in which case no headers are set.
+func (c *CORSPolicy) setOrigin(w http.ResponseWriter, origin string) bool {
+	if !c.allowOrigin(origin) {
+		return false
+	}
+	h := w.Header()
+	if c.allowAnyOrigin() && !c.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+	}
+	if c.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+//handleActual sets the CORS headers for a request that is not a preflight request.
+func (c *CORSPolicy) handleActual(w http.ResponseWriter, r *http.Request) {
+	if !c.setOrigin(w, r.Header.Get("Origin")) {
+		return
+	}
+	if len(c.ExposedHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+	}
+}
+
+//handlePreflight answers a preflight request. registered is the list of methods the path
+// can be requested with. the response is always 204, but the CORS headers are only set if
+// the origin, method and headers are allowed.
+func (c *CORSPolicy) handlePreflight(w http.ResponseWriter, r *http.Request, registered []string) {
+	defer w.WriteHeader(http.StatusNoContent)
+
+	methods := registered
+	if len(c.AllowedMethods) > 0 {
+		methods = []string{}
+		for _, m := range c.AllowedMethods {
+			if containsString(registered, m) {
+				methods = append(methods, m)
+			}
+		}
+	}
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	requested := splitHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+	if !containsString(methods, method) || !c.allowHeaders(requested) {
+		return
+	}
+	if !c.setOrigin(w, r.Header.Get("Origin")) {
+		return
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(requested) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	}
+	if c.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+	}
+}
+
+//isPreflight reports if the request is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+//splitHeaderList splits a comma separated header value into its trimmed, non empty items.
+func splitHeaderList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
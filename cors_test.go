@@ -0,0 +1,193 @@
+package validapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSAllowOrigin(t *testing.T) {
+	policy := &CORSPolicy{
+		AllowedOrigins:  []string{"https://example.com", "https://*.example.org"},
+		AllowOriginFunc: func(origin string) bool { return origin == "https://func.com" },
+	}
+
+	testData := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://example.com", true},
+		{"https://api.example.org", true},
+		{"https://example.org", false},
+		{"http://api.example.org", false},
+		{"https://func.com", true},
+		{"https://evil.com", false},
+	}
+
+	for _, i := range testData {
+		if got := policy.allowOrigin(i.origin); got != i.want {
+			t.Errorf("%v: got %v want %v", i.origin, got, i.want)
+		}
+	}
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	api := New()
+	api.CORS = &CORSPolicy{
+		AllowedOrigins:   []string{"*", "https://example.com"},
+		AllowCredentials: true,
+	}
+	api.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	testData := []struct {
+		origin      string
+		allow       string
+		credentials string
+	}{
+		{"https://evil.com", "", ""},
+		{"https://example.com", "https://example.com", "true"},
+	}
+
+	for _, i := range testData {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Origin", i.origin)
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != i.allow {
+			t.Errorf("%v: got allow origin %q want %q", i.origin, got, i.allow)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != i.credentials {
+			t.Errorf("%v: got allow credentials %q want %q", i.origin, got, i.credentials)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	api := New()
+	api.CORS = &CORSPolicy{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowedHeaders:   []string{"Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	api.Get("/users", ok)
+	api.Post("/users", ok)
+
+	t.Run("allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type")
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+
+		want := map[string]string{
+			"Access-Control-Allow-Origin":      "https://example.com",
+			"Access-Control-Allow-Methods":     "GET, HEAD, OPTIONS, POST",
+			"Access-Control-Allow-Headers":     "content-type",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Max-Age":           "600",
+		}
+		for k, v := range want {
+			if got := rec.Header().Get(k); got != v {
+				t.Errorf("%v: got %q want %q", k, got, v)
+			}
+		}
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("got status %v want %v", rec.Code, http.StatusNoContent)
+		}
+	})
+
+	t.Run("method not registered", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("wanted no allow origin header got %v", got)
+		}
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Origin", "https://example.com")
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+			t.Errorf("got %q want https://example.com", got)
+		}
+	})
+}
+
+func TestCORSRouterOverride(t *testing.T) {
+	api := New()
+	api.CORS = &CORSPolicy{AllowedOrigins: []string{"https://admin.example.com"}}
+	public := NewRouter()
+	public.CORS = &CORSPolicy{AllowedOrigins: []string{"*"}}
+	public.Get("/items", func(w http.ResponseWriter, r *http.Request) {})
+	api.Mount("/public", public)
+	api.Get("/admin", func(w http.ResponseWriter, r *http.Request) {})
+
+	testData := []struct {
+		path string
+		want string
+	}{
+		{"/public/items", "*"},
+		{"/admin", ""},
+	}
+
+	for _, i := range testData {
+		req := httptest.NewRequest(http.MethodGet, i.path, nil)
+		req.Header.Set("Origin", "https://anyone.com")
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != i.want {
+			t.Errorf("%v: got %q want %q", i.path, got, i.want)
+		}
+	}
+}
+
+func TestCORSPolicyOfMatchedRoute(t *testing.T) {
+	api := New()
+	api.CORS = &CORSPolicy{AllowedOrigins: []string{"https://a.com"}}
+	public := NewRouter()
+	public.CORS = &CORSPolicy{AllowedOrigins: []string{"*"}}
+	public.Get("/x", func(w http.ResponseWriter, r *http.Request) {})
+	api.Mount("/pub", public)
+	api.Get("/:any/y", func(w http.ResponseWriter, r *http.Request) {})
+
+	testData := []struct {
+		name    string
+		method  string
+		request string
+		want    string
+	}{
+		{"actual root route", http.MethodGet, "", ""},
+		{"preflight root route", http.MethodOptions, http.MethodGet, ""},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			req := httptest.NewRequest(i.method, "/pub/y", nil)
+			req.Header.Set("Origin", "https://evil.com")
+			if i.request != "" {
+				req.Header.Set("Access-Control-Request-Method", i.request)
+			}
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, req)
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != i.want {
+				t.Errorf("got %q want %q", got, i.want)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/pub/x", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("got %q want *", got)
+	}
+}
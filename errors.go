@@ -0,0 +1,127 @@
+package validapi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+//DefaultMaxErrors the number of errors collected when validating a body before validation
+// stops, unless the PropertyGroup sets its own limit with MaxErrors.
+const DefaultMaxErrors = 100
+
+//ErrNotJSONObject is returned when a body that should be validated is not a json object.
+var ErrNotJSONObject = errors.New("body must be a json object")
+
+//ErrBodyNotValidated is returned by BodyAs when the request body was not validated.
+var ErrBodyNotValidated = errors.New("request body was not validated")
+
+//Codes used by ValidationError to describe why a value failed validation.
+const (
+	CodeTypeMismatch    = "type_mismatch"
+	CodeOutOfRange      = "out_of_range"
+	CodeRequired        = "required"
+	CodeUnknownProperty = "unknown_property"
+	CodeEnum            = "enum"
+	CodeRegex           = "regex"
+	CodeMinimum         = "minimum"
+	CodeMaximum         = "maximum"
+	CodeMultipleOf      = "multiple_of"
+	CodeMinLength       = "min_length"
+	CodeMaxLength       = "max_length"
+	CodeMinItems        = "min_items"
+	CodeMaxItems        = "max_items"
+	CodeUniqueItems     = "unique_items"
+	CodeCustom          = "custom"
+)
+
+//ValidationError describes a single violation found while validating a body. Path is a
+// JSON Pointer (RFC 6901) to the value that failed, such as /User/0/Name.
+type ValidationError struct {
+	Path     string      `json:"path"`
+	Code     string      `json:"code"`
+	Message  string      `json:"message"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+	//Rule the name of the rule that failed, if the error was returned by a rule.
+	Rule string `json:"rule,omitempty"`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+//ValidationErrors the list of errors found while validating a body.
+type ValidationErrors []*ValidationError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(ve))
+	for _, err := range ve {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+//ruleError turns the error returned by a rule into a ValidationError. rules can return a
+// ValidationError to set their own code and expected value, any other error is reported
+// with the custom code.
+func ruleError(r Rule, path string, value interface{}, err error) *ValidationError {
+	ve := &ValidationError{Code: CodeCustom, Message: err.Error()}
+	if ruleErr, ok := err.(*ValidationError); ok {
+		copied := *ruleErr
+		ve = &copied
+	}
+	ve.Path = path
+	ve.Actual = value
+	if ve.Rule == "" {
+		ve.Rule = ruleName(r)
+	}
+	return ve
+}
+
+//ruleName returns the name of a rule used in ValidationError.
+func ruleName(r Rule) string {
+	if cr, ok := r.(CustomRule); ok {
+		return cr.name
+	}
+	return reflect.TypeOf(r).Name()
+}
+
+//joinPath adds the key to a JSON Pointer, escaping it as described in RFC 6901.
+func joinPath(path, key string) string {
+	key = strings.Replace(key, "~", "~0", -1)
+	key = strings.Replace(key, "/", "~1", -1)
+	return path + "/" + key
+}
+
+//validator collects the errors found while walking a body, up to a maximum.
+type validator struct {
+	errs ValidationErrors
+	max  int
+}
+
+//newValidator creates a validator that accepts up to max errors. a max of zero or less
+// uses DefaultMaxErrors.
+func newValidator(max int) *validator {
+	if max <= 0 {
+		max = DefaultMaxErrors
+	}
+	return &validator{max: max}
+}
+
+//add records the error and reports if more errors will be accepted.
+func (v *validator) add(err *ValidationError) bool {
+	v.errs = append(v.errs, err)
+	return len(v.errs) < v.max
+}
+
+//err returns the errors collected, or nil if there are none.
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
@@ -0,0 +1,181 @@
+package validapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]func(string) bool{
+		"email":     isEmail,
+		"url":       isURL,
+		"uuid":      isUUID,
+		"date-time": isDateTime,
+		"date":      isDate,
+		"ip":        isIP,
+		"ipv4":      isIPv4,
+		"ipv6":      isIPv6,
+		"hostname":  isHostname,
+		"base64":    isBase64,
+	}
+)
+
+//RegisterFormat adds a named format that can be used with NewFormatRule. check should
+// report if a string is in the format. will panic if the name is already registered.
+func RegisterFormat(name string, check func(string) bool) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if _, present := formats[name]; present {
+		panic(fmt.Errorf("duplicated format name: %v", name))
+	}
+	formats[name] = check
+}
+
+//FormatRule checks that a string is in a named format. the built in formats are email, url,
+// uuid, date-time, date, ip, ipv4, ipv6, hostname and base64, and more can be added with
+// RegisterFormat. errors use the code "format_" followed by the format name, with dashes
+// replaced by underscores, such as format_date_time.
+type FormatRule struct {
+	name  string
+	check func(string) bool
+}
+
+//NewFormatRule creates a FormatRule for the named format. it returns a blank rule and an
+// error if the format is not registered.
+func NewFormatRule(name string) (FormatRule, error) {
+	formatsMu.RLock()
+	check, ok := formats[name]
+	formatsMu.RUnlock()
+	if !ok {
+		return FormatRule{}, fmt.Errorf("unknown format %v", name)
+	}
+	return FormatRule{name: name, check: check}, nil
+}
+
+func (r FormatRule) validate(i interface{}) error {
+	if r.check(i.(string)) {
+		return nil
+	}
+	return &ValidationError{
+		Code:     "format_" + strings.Replace(r.name, "-", "_", -1),
+		Message:  fmt.Sprintf("not a valid %v", r.name),
+		Expected: r.name,
+	}
+}
+
+//Params returns the name of the format.
+func (r FormatRule) Params() map[string]interface{} {
+	return map[string]interface{}{"format": r.name}
+}
+
+func (r FormatRule) rulevalidation(p Props) error {
+	return stringRuleValidation("format", p)
+}
+
+//isEmail checks for a plain address such as user@example.com. display names, comments and
+// quoted local parts are not accepted.
+func isEmail(s string) bool {
+	at := strings.LastIndexByte(s, '@')
+	if at < 1 || at > 64 || len(s) > 254 {
+		return false
+	}
+	local, domain := s[:at], s[at+1:]
+	if local[0] == '.' || local[len(local)-1] == '.' || strings.Contains(local, "..") {
+		return false
+	}
+	for _, c := range local {
+		if !isAlphaNumeric(c) && !strings.ContainsRune("!#$%&'*+-/=?^_`{|}~.", c) {
+			return false
+		}
+	}
+	return strings.Contains(domain, ".") && isHostname(domain)
+}
+
+//isURL checks for an absolute url with a scheme and a host.
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != "" && !strings.ContainsAny(s, " \t\n")
+}
+
+//isUUID checks for the canonical 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !isHex(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//isDateTime checks for an RFC 3339 date and time, such as 2020-01-02T15:04:05Z.
+func isDateTime(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
+
+//isDate checks for an RFC 3339 full date, such as 2020-01-02.
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+func isIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+func isIPv4(s string) bool {
+	return !strings.Contains(s, ":") && net.ParseIP(s) != nil
+}
+
+func isIPv6(s string) bool {
+	return strings.Contains(s, ":") && net.ParseIP(s) != nil
+}
+
+//isHostname checks for an RFC 1123 host name. labels are 1 to 63 letters, digits or hyphens
+// and cannot start or end with a hyphen.
+func isHostname(s string) bool {
+	if len(s) == 0 || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !isAlphaNumeric(c) && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//isBase64 checks for padded standard base64.
+func isBase64(s string) bool {
+	_, err := base64.StdEncoding.Strict().DecodeString(s)
+	return err == nil
+}
+
+func isAlphaNumeric(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isHex(c rune) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,106 @@
+package validapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatRule(t *testing.T) {
+	testData := []struct {
+		format string
+		value  string
+		pass   bool
+	}{
+		{"email", "user@example.com", true},
+		{"email", "first.last+tag@mail.example.co", true},
+		{"email", "Name <user@example.com>", false},
+		{"email", "user@localhost", false},
+		{"email", "user..name@example.com", false},
+		{"url", "https://example.com/path?q=1", true},
+		{"url", "example.com", false},
+		{"url", "/relative/path", false},
+		{"uuid", "3f2b8c1e-9d4a-4f6e-8b7c-1a2b3c4d5e6f", true},
+		{"uuid", "3f2b8c1e9d4a4f6e8b7c1a2b3c4d5e6f", false},
+		{"date-time", "2020-01-02T15:04:05Z", true},
+		{"date-time", "2020-01-02T15:04:05.123+02:00", true},
+		{"date-time", "2020-01-02 15:04:05", false},
+		{"date", "2020-02-29", true},
+		{"date", "2021-02-29", false},
+		{"ip", "::1", true},
+		{"ipv4", "192.168.0.1", true},
+		{"ipv4", "::ffff:192.168.0.1", false},
+		{"ipv4", "256.1.1.1", false},
+		{"ipv6", "2001:db8::1", true},
+		{"ipv6", "192.168.0.1", false},
+		{"hostname", "api.example.com", true},
+		{"hostname", "-bad.example.com", false},
+		{"hostname", "under_score.com", false},
+		{"hostname", strings.Repeat("a", 64) + ".com", false},
+		{"base64", "aGVsbG8=", true},
+		{"base64", "aGVsbG8", false},
+	}
+
+	for _, i := range testData {
+		rule, err := NewFormatRule(i.format)
+		if err != nil {
+			t.Fatalf("could not build rule for testing. err: %v", err.Error())
+		}
+		err = rule.validate(i.value)
+		if i.pass && err != nil {
+			t.Errorf("%v %q: wanted nil got %v", i.format, i.value, err.Error())
+		}
+		if !i.pass && err == nil {
+			t.Errorf("%v %q: wanted error got nil", i.format, i.value)
+		}
+	}
+}
+
+func TestFormatRuleCode(t *testing.T) {
+	rule, _ := NewFormatRule("date-time")
+	err := NewProperty("created", String).AddRules(rule).validate("/created", "yesterday")
+	errs, ok := err.(ValidationErrors)
+	if !ok || errs[0].Code != "format_date_time" {
+		t.Errorf("wanted format_date_time error got %v", err)
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("even-length", func(s string) bool { return len(s)%2 == 0 })
+	t.Cleanup(func() {
+		formatsMu.Lock()
+		delete(formats, "even-length")
+		formatsMu.Unlock()
+	})
+
+	rule, err := NewFormatRule("even-length")
+	if err != nil {
+		t.Fatalf("wanted nil got %v", err.Error())
+	}
+	if err := rule.validate("ab"); err != nil {
+		t.Errorf("wanted nil got %v", err.Error())
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := NewFormatRule("nope"); err == nil {
+			t.Error("wanted error got nil")
+		}
+	})
+
+	t.Run("duplicate format", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("wanted panic got nil")
+			}
+		}()
+		RegisterFormat("email", isEmail)
+	})
+
+	t.Run("Should fail to add to prop", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("wanted an error, got nil")
+			}
+		}()
+		_ = NewProperty("test", Int).AddRules(rule)
+	})
+}
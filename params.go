@@ -0,0 +1,133 @@
+package validapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+type contextKey int
+
+const (
+	paramsKey contextKey = iota
+	bodyKey
+)
+
+//PathParam represents a single named path parameter captured while routing a request.
+type PathParam struct {
+	Key   string
+	Value string
+	value interface{}
+}
+
+//Params the path parameters captured for a request, in the order they appear in the path.
+type Params []PathParam
+
+//Get returns the raw value of the named parameter, or an empty string if it does not exist.
+func (ps Params) Get(name string) string {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+//ParamsFromRequest returns all path parameters captured for the request.
+func ParamsFromRequest(r *http.Request) Params {
+	ps, _ := r.Context().Value(paramsKey).(Params)
+	return ps
+}
+
+//Param returns the raw value of the named path parameter, or an empty string if it does not exist.
+func Param(r *http.Request, name string) string {
+	return ParamsFromRequest(r).Get(name)
+}
+
+//ParamValue returns the value of the named path parameter converted to the Type it was
+// declared with on the Endpoint. parameters without a declared Type are returned as strings.
+// returns nil if the parameter does not exist.
+func ParamValue(r *http.Request, name string) interface{} {
+	for _, p := range ParamsFromRequest(r) {
+		if p.Key == name {
+			if p.value != nil {
+				return p.value
+			}
+			return p.Value
+		}
+	}
+	return nil
+}
+
+//ParamInt returns the value of the named path parameter as an int. it should be used with
+// parameters declared with an integer Type such as Int or Uint64 on the Endpoint, which
+// guarantees the conversion succeeds. values that do not fit in an int are truncated.
+// returns 0 if the parameter does not exist or is not an integer.
+func ParamInt(r *http.Request, name string) int {
+	v := ParamValue(r, name)
+	if s, ok := v.(string); ok {
+		i, _ := strconv.Atoi(s)
+		return i
+	}
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(val.Uint())
+	}
+	return 0
+}
+
+//withParams returns a shallow copy of the request with the params stored in its context.
+func withParams(r *http.Request, ps Params) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), paramsKey, ps))
+}
+
+//convertParam converts the raw value of a path segment to the type of the property.
+func convertParam(p *Property, raw string) (interface{}, error) {
+	var (
+		val interface{}
+		err error
+	)
+	out := reflect.New(p.propType).Elem()
+	switch out.Kind() {
+	case reflect.String:
+		val = raw
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(raw, 10, out.Type().Bits()); err == nil {
+			out.SetInt(i)
+			val = out.Interface()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i uint64
+		if i, err = strconv.ParseUint(raw, 10, out.Type().Bits()); err == nil {
+			out.SetUint(i)
+			val = out.Interface()
+		}
+	case reflect.Float32, reflect.Float64:
+		val, err = strconv.ParseFloat(raw, 64)
+	case reflect.Bool:
+		val, err = strconv.ParseBool(raw)
+	default:
+		err = fmt.Errorf("unsupported parameter type %v", p.propType.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid type. could not convert %v to %v", raw, p.propType.String())
+	}
+	return val, nil
+}
+
+//paramNames returns the names of the variable and catch-all segments of a path pattern.
+func paramNames(pattern string) []string {
+	names := []string{}
+	for _, seg := range splitPath(pattern) {
+		if len(seg) > 0 && (seg[0] == ':' || seg[0] == '*') {
+			names = append(names, seg[1:])
+		}
+	}
+	return names
+}
@@ -0,0 +1,81 @@
+package validapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	api := New()
+	var id, slug string
+	api.Get("/users/:id/posts/:slug", func(w http.ResponseWriter, r *http.Request) {
+		id = Param(r, "id")
+		slug = Param(r, "slug")
+	})
+
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/12/posts/hello", nil))
+	if id != "12" || slug != "hello" {
+		t.Errorf("got id %v slug %v, want 12 and hello", id, slug)
+	}
+}
+
+func TestTypedParams(t *testing.T) {
+	api := New()
+	var got interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = ParamValue(r, "id")
+		w.WriteHeader(http.StatusOK)
+	}
+	uuid, _ := NewRegexRule("^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$")
+	api.Get("/users/:id", handler).Params(NewProperty("id", Int))
+	api.Get("/sessions/:id", handler).Params(NewProperty("id", String).AddRules(uuid))
+
+	testData := []struct {
+		path string
+		want int
+	}{
+		{"/users/12", http.StatusOK},
+		{"/users/abc", http.StatusBadRequest},
+		{"/users/1.5", http.StatusBadRequest},
+		{"/sessions/3f2b8c1e-9d4a-4f6e-8b7c-1a2b3c4d5e6f", http.StatusOK},
+		{"/sessions/12", http.StatusBadRequest},
+	}
+
+	for _, i := range testData {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, i.path, nil))
+		if rec.Code != i.want {
+			t.Errorf("%v: got status %v want %v", i.path, rec.Code, i.want)
+		}
+	}
+
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if got != 42 {
+		t.Errorf("wanted typed value 42 got %v", got)
+	}
+}
+
+func TestParamInt(t *testing.T) {
+	for _, typ := range []Type{Int, Int32, Int64, Uint} {
+		api := New()
+		var got int
+		api.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+			got = ParamInt(r, "id")
+		}).Params(NewProperty("id", typ))
+
+		api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+		if got != 7 {
+			t.Errorf("%v: got %v want 7", typ, got)
+		}
+	}
+}
+
+func TestUnknownParam(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("wanted panic got nil")
+		}
+	}()
+	New().Get("/users/:id", nil).Params(NewProperty("name", String))
+}
@@ -0,0 +1,49 @@
+package validapi
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//ValidationProblemType the type URI of the problem details written by ProblemRenderer when
+// a request fails validation. it can be replaced with a URL documenting the error.
+var ValidationProblemType = "urn:validapi:problem:validation-error"
+
+//ErrorRenderer writes the response for a request that could not be handled. err is a
+// ValidationErrors when the path parameters or the body failed validation.
+type ErrorRenderer func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+//Problem problem details for http APIs as described in RFC 7807. Errors is an extension
+// member listing every field that failed validation.
+type Problem struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   ValidationErrors `json:"errors,omitempty"`
+}
+
+//ProblemRenderer the default ErrorRenderer. it writes the error as application/problem+json.
+// validation failures use ValidationProblemType and list each violation in the errors member,
+// other errors use the about:blank type with the status text as title.
+func ProblemRenderer(w http.ResponseWriter, r *http.Request, status int, err error) {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: r.URL.Path,
+	}
+	if errs, ok := err.(ValidationErrors); ok {
+		problem.Type = ValidationProblemType
+		problem.Title = "Validation Failed"
+		problem.Detail = "the request contains invalid values. see errors for details."
+		problem.Errors = errs
+	} else if err != nil && err.Error() != problem.Title {
+		problem.Detail = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
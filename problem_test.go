@@ -0,0 +1,70 @@
+package validapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProblemRenderer(t *testing.T) {
+	api := New()
+	api.Post("/users", func(w http.ResponseWriter, r *http.Request) {}).Body(NewPropertyGroup().AddProperties(
+		NewProperty("Name", String).Required(),
+		NewProperty("ID", Int),
+	))
+
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"ID": "12"}`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got status %v want %v", rec.Code, http.StatusBadRequest)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("got content type %v want application/problem+json", got)
+	}
+
+	var problem Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Type != ValidationProblemType || problem.Status != http.StatusBadRequest || problem.Title == "" {
+		t.Errorf("unexpected problem %+v", problem)
+	}
+	if len(problem.Errors) != 2 || problem.Errors[0].Path != "/ID" || problem.Errors[1].Code != CodeRequired {
+		t.Errorf("unexpected errors %v", problem.Errors)
+	}
+}
+
+func TestProblemRendererNotFound(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	var problem Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Type != "about:blank" || problem.Status != http.StatusNotFound || problem.Detail != "" {
+		t.Errorf("unexpected problem %+v", problem)
+	}
+}
+
+func TestCustomErrorRenderer(t *testing.T) {
+	api := New()
+	var got error
+	api.ErrorRenderer = func(w http.ResponseWriter, r *http.Request, status int, err error) {
+		got = err
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	api.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {}).Params(NewProperty("id", Int))
+
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("got status %v want %v", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if errs, ok := got.(ValidationErrors); !ok || errs[0].Path != "/id" {
+		t.Errorf("wanted ValidationErrors for /id got %v", got)
+	}
+}
@@ -1,17 +1,26 @@
 package validapi
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
 	"strconv"
 )
 
 //Props the interface that should represent a single property in a json object.
-// currently Property and Object Property implement it.
+// currently Property, ObjectProperty, ArrayProperty and MapProperty implement it.
 type Props interface {
 	getName() string
 	getType() Type
+	isRequired() bool
 	validate(string, interface{}) error
+	//collect validates the value found at the path and adds every error found to the
+	// validator. it returns false once the validator does not accept more errors.
+	collect(string, interface{}, *validator) bool
 }
 
 //Property represents a single property in a request body.
@@ -19,6 +28,8 @@ type Property struct {
 	Name     string
 	propType Type
 	rules    []Rule
+	required bool
+	nullable bool
 }
 
 //NewProperty creates a property with a blank rule set.
@@ -38,6 +49,23 @@ func (p Property) getType() Type {
 	return p.propType
 }
 
+func (p Property) isRequired() bool {
+	return p.required
+}
+
+//Required marks the Property as required. validation will fail if the key is missing
+// from the object it should be in.
+func (p *Property) Required() *Property {
+	p.required = true
+	return p
+}
+
+//Nullable allows the value of the Property to be null. rules are not applied to null values.
+func (p *Property) Nullable() *Property {
+	p.nullable = true
+	return p
+}
+
 //AddRules will take the rules provided and add them to the Property,
 // checking if they are valid first. If not, it will print a msg stating
 // it has been ignored.
@@ -54,30 +82,56 @@ func (p *Property) AddRules(rules ...Rule) *Property {
 }
 
 func (p Property) validate(key string, value interface{}) error {
+	v := newValidator(1)
+	p.collect(key, value, v)
+	return v.err()
+}
+
+func (p Property) collect(key string, value interface{}, v *validator) bool {
+	if value == nil {
+		if p.nullable {
+			return true
+		}
+		return v.add(typeMismatch(key, "null", p.propType.String()))
+	}
 	valueType := reflect.TypeOf(value)
 
-	//When Json is decoded in go, all JSON numbers are converted to float64 types.
-	// this means we need to handle integer checking differently.
-	if p.propType == Int {
-		if !valueType.ConvertibleTo(Int) {
-			return fmt.Errorf("%v: invalid type. got %v, want %v", key, valueType.String(), p.propType.String())
+	//When Json is decoded in go, JSON numbers are converted to float64 or json.Number
+	// types. numbers are converted to the property's type before the rules are applied.
+	if isNumeric(p.propType) {
+		num, err := convertNumber(key, value, p.propType)
+		if err != nil {
+			return v.add(err)
 		}
+		value = num
 
 	} else if valueType != p.propType {
-		return fmt.Errorf("%v: invalid type. got %v, want %v", key, valueType.String(), p.propType.String())
+		return v.add(typeMismatch(key, valueType.String(), p.propType.String()))
 	}
 	for _, rule := range p.rules {
 		err := rule.validate(value)
-		if err != nil {
-			return fmt.Errorf("%v: %v", key, err.Error())
+		if err != nil && !v.add(ruleError(rule, key, value, err)) {
+			return false
 		}
 	}
-	return nil
+	return true
+}
+
+//typeMismatch builds the error for a value that does not have the expected type.
+func typeMismatch(path, got, want string) *ValidationError {
+	return &ValidationError{
+		Path:     path,
+		Code:     CodeTypeMismatch,
+		Message:  fmt.Sprintf("invalid type. got %v, want %v", got, want),
+		Expected: want,
+		Actual:   got,
+	}
 }
 
 //PropertyGroup wrapper used to be sure property names are unique when applied to a route.
 type PropertyGroup struct {
 	properties map[string]Props
+	maxErrors  int
 }
 
 //NewPropertyGroup creates a PropertyGroup with no properties.
@@ -98,19 +152,74 @@ func (pg *PropertyGroup) AddProperties(props ...Props) *PropertyGroup {
 	return pg
 }
 
-func (pg *PropertyGroup) validateGroup(body map[string]interface{}) error {
-	for key, val := range body {
-		if property, ok := pg.properties[key]; ok {
-			err := property.validate(key, val)
-			if err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf(" %v is not a valid Property", key)
+//MaxErrors sets the number of errors collected before validation stops. by default the
+// whole body is validated and up to DefaultMaxErrors errors are returned. the limit applies
+// to groups nested inside the group as well.
+func (pg *PropertyGroup) MaxErrors(n int) *PropertyGroup {
+	pg.maxErrors = n
+	return pg
+}
+
+//StopOnFirstError stops validation at the first error found, for cheap rejection of
+// invalid bodies. it is the same as MaxErrors(1).
+func (pg *PropertyGroup) StopOnFirstError() *PropertyGroup {
+	return pg.MaxErrors(1)
+}
+
+//Validate validates the body against the group. the returned error is a ValidationErrors
+// listing every error found, up to the group's limit, with paths relative to the root of
+// the body. numbers in the body can be float64 or json.Number values.
+func (pg *PropertyGroup) Validate(body map[string]interface{}) error {
+	v := newValidator(pg.maxErrors)
+	groupvalidator("", body, pg.properties, v)
+	return v.err()
+}
+
+//ValidateJSON decodes the json object in data and validates it against the group. it
+// returns the decoded body, with numbers as json.Number so large integers keep their
+// precision. the error is ErrNotJSONObject if data is valid json that is not an object,
+// wraps the decoding error if data is not valid json or has data after the object, and is
+// a ValidationErrors if the body is invalid.
+func (pg *PropertyGroup) ValidateJSON(data []byte) (map[string]interface{}, error) {
+	return pg.ValidateReader(bytes.NewReader(data))
+}
+
+//ValidateReader works like ValidateJSON, but reads the json object from r.
+func (pg *PropertyGroup) ValidateReader(r io.Reader) (map[string]interface{}, error) {
+	var val interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&val); err != nil {
+		if err == io.EOF {
+			err = errors.New("body is empty")
 		}
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	if err := decoder.Decode(&val); err != io.EOF {
+		return nil, errors.New("invalid json: unexpected data after the json value")
 	}
 
-	return nil
+	body, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, ErrNotJSONObject
+	}
+	if err := pg.Validate(body); err != nil {
+		return body, err
+	}
+	return body, nil
+}
+
+//requiredNames returns the sorted names of the required props, so missing properties
+// are always reported in the same order.
+func requiredNames(props map[string]Props) []string {
+	names := []string{}
+	for name, prop := range props {
+		if prop.isRequired() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
 //ObjectProperty represents a property that would be an object type in json
@@ -120,17 +229,41 @@ type ObjectProperty struct {
 	Name     string
 	propType Type
 	slice    bool
+	required bool
+	nullable bool
 	group    *PropertyGroup
+	rules    []Rule
 }
 
 func (o ObjectProperty) getName() string {
 	return o.Name
 }
 
+//getType returns Array for ObjectProperties that are slices, so array rules can be added.
 func (o ObjectProperty) getType() Type {
+	if o.slice {
+		return Array
+	}
 	return o.propType
 }
 
+func (o ObjectProperty) isRequired() bool {
+	return o.required
+}
+
+//Required marks the ObjectProperty as required. validation will fail if the key is missing
+// from the object it should be in.
+func (o *ObjectProperty) Required() *ObjectProperty {
+	o.required = true
+	return o
+}
+
+//Nullable allows the value of the ObjectProperty to be null.
+func (o *ObjectProperty) Nullable() *ObjectProperty {
+	o.nullable = true
+	return o
+}
+
 //NewObjectProperty creates a new Object Property with the name provided and sets the slice var
 func NewObjectProperty(name string, slice bool) *ObjectProperty {
 	return &ObjectProperty{
@@ -149,6 +282,21 @@ func (o *ObjectProperty) UsePropertyGroup(pg *PropertyGroup) *ObjectProperty {
 	return o
 }
 
+//AddRules adds rules that are applied to a slice of objects as a whole, such as
+// MinItemsRule. will panic if the ObjectProperty is not a slice or a rule cannot be used
+// with arrays.
+func (o *ObjectProperty) AddRules(rules ...Rule) *ObjectProperty {
+	for _, r := range rules {
+		err := r.rulevalidation(o)
+		if err == nil {
+			o.rules = append(o.rules, r)
+		} else {
+			panic(fmt.Errorf("could not add rules to ObjectProperty %v. error: %v", o.Name, err.Error()))
+		}
+	}
+	return o
+}
+
 //AddProperties add Base Properties to the property group of the object property.
 func (o *ObjectProperty) AddProperties(p ...Props) *ObjectProperty {
 	o.group.AddProperties(p...)
@@ -156,42 +304,263 @@ func (o *ObjectProperty) AddProperties(p ...Props) *ObjectProperty {
 }
 
 func (o ObjectProperty) validate(key string, val interface{}) error {
-	if o.slice && reflect.TypeOf(val).Kind() == reflect.Slice {
+	v := newValidator(1)
+	o.collect(key, val, v)
+	return v.err()
+}
+
+func (o ObjectProperty) collect(key string, val interface{}, v *validator) bool {
+	if val == nil && o.nullable {
+		return true
+	}
+	if o.slice && val != nil && reflect.TypeOf(val).Kind() == reflect.Slice {
+		for _, rule := range o.rules {
+			err := rule.validate(val)
+			if err != nil && !v.add(ruleError(rule, key, nil, err)) {
+				return false
+			}
+		}
 		reflectVal := reflect.ValueOf(val)
 		for i := 0; i < reflectVal.Len(); i++ {
-			err := objectvalidator(strconv.Itoa(i), reflectVal.Index(i).Interface(), o.group.properties)
-			if err != nil {
-				return fmt.Errorf("%v.%v", key, err.Error())
+			if !objectvalidator(joinPath(key, strconv.Itoa(i)), reflectVal.Index(i).Interface(), o.group.properties, v) {
+				return false
 			}
 		}
-	} else {
-		err := objectvalidator(key, val, o.group.properties)
-		if err != nil {
-			return err
-		}
+		return true
 	}
-	return nil
+	return objectvalidator(key, val, o.group.properties, v)
 }
 
 // function used by objectProperty.validate to validate a value. It has been
 // written here so it can be used in both standard and array instances.
-func objectvalidator(key string, val interface{}, props map[string]Props) error {
-	if reflect.TypeOf(val).Kind() == reflect.Map {
-		mapIter := reflect.ValueOf(val).MapRange()
-		for mapIter.Next() {
-			k := mapIter.Key().String()
-			v := mapIter.Value().Interface()
-			if prop, ok := props[k]; ok {
-				err := prop.validate(k, v)
-				if err != nil {
-					return fmt.Errorf("%v.%v", key, err.Error())
-				}
-			} else {
-				return fmt.Errorf("%v is not a valid prop", k)
+func objectvalidator(key string, val interface{}, props map[string]Props, v *validator) bool {
+	if val == nil || reflect.TypeOf(val).Kind() != reflect.Map {
+		kind := "null"
+		if val != nil {
+			kind = reflect.TypeOf(val).Kind().String()
+		}
+		return v.add(typeMismatch(key, kind, "object"))
+	}
+
+	body := make(map[string]interface{})
+	mapIter := reflect.ValueOf(val).MapRange()
+	for mapIter.Next() {
+		body[mapIter.Key().String()] = mapIter.Value().Interface()
+	}
+	return groupvalidator(key, body, props, v)
+}
+
+//groupvalidator validates each key of the body against the props, in sorted order, followed
+// by the required props that are missing. path is the path of the object the body was found
+// at, and is empty for the top level of a request body.
+func groupvalidator(path string, body map[string]interface{}, props map[string]Props, v *validator) bool {
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if prop, ok := props[key]; ok {
+			if !prop.collect(joinPath(path, key), body[key], v) {
+				return false
 			}
+		} else if !v.add(&ValidationError{Path: joinPath(path, key), Code: CodeUnknownProperty, Message: "not a valid property"}) {
+			return false
+		}
+	}
+
+	for _, name := range requiredNames(props) {
+		if _, ok := body[name]; !ok {
+			if !v.add(&ValidationError{Path: joinPath(path, name), Code: CodeRequired, Message: "required property is missing"}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//ArrayProperty represents a property that is a json array of a basic type, such as a list
+// of strings. item rules are applied to every element of the array, and rules added to the
+// ArrayProperty itself are applied to the array as a whole.
+type ArrayProperty struct {
+	Name     string
+	propType Type
+	item     *Property
+	rules    []Rule
+	required bool
+	nullable bool
+}
+
+//NewArrayProperty creates an ArrayProperty whose elements must be of the item Type.
+func NewArrayProperty(name string, item Type) *ArrayProperty {
+	return &ArrayProperty{
+		Name:     name,
+		propType: Array,
+		item:     NewProperty(name, item),
+		rules:    []Rule{},
+	}
+}
+
+func (a ArrayProperty) getName() string {
+	return a.Name
+}
+
+func (a ArrayProperty) getType() Type {
+	return a.propType
+}
+
+func (a ArrayProperty) isRequired() bool {
+	return a.required
+}
+
+//Required marks the ArrayProperty as required. validation will fail if the key is missing
+// from the object it should be in.
+func (a *ArrayProperty) Required() *ArrayProperty {
+	a.required = true
+	return a
+}
+
+//Nullable allows the value of the ArrayProperty to be null. use NullableItems to allow
+// null elements instead.
+func (a *ArrayProperty) Nullable() *ArrayProperty {
+	a.nullable = true
+	return a
+}
+
+//NullableItems allows the elements of the array to be null.
+func (a *ArrayProperty) NullableItems() *ArrayProperty {
+	a.item.Nullable()
+	return a
+}
+
+//AddItemRules adds rules that every element of the array is validated against. will panic
+// if a rule cannot be used with the item Type.
+func (a *ArrayProperty) AddItemRules(rules ...Rule) *ArrayProperty {
+	a.item.AddRules(rules...)
+	return a
+}
+
+//AddRules adds rules that are applied to the array as a whole, such as MinItemsRule.
+// will panic if a rule cannot be used with arrays.
+func (a *ArrayProperty) AddRules(rules ...Rule) *ArrayProperty {
+	for _, r := range rules {
+		err := r.rulevalidation(a)
+		if err == nil {
+			a.rules = append(a.rules, r)
+		} else {
+			panic(fmt.Errorf("could not add rules to ArrayProperty %v. error: %v", a.Name, err.Error()))
+		}
+	}
+	return a
+}
+
+func (a ArrayProperty) validate(key string, val interface{}) error {
+	v := newValidator(1)
+	a.collect(key, val, v)
+	return v.err()
+}
+
+func (a ArrayProperty) collect(key string, val interface{}, v *validator) bool {
+	if val == nil {
+		if a.nullable {
+			return true
+		}
+		return v.add(typeMismatch(key, "null", "array"))
+	}
+	if kind := reflect.TypeOf(val).Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return v.add(typeMismatch(key, reflect.TypeOf(val).String(), "array"))
+	}
+
+	for _, rule := range a.rules {
+		err := rule.validate(val)
+		if err != nil && !v.add(ruleError(rule, key, nil, err)) {
+			return false
+		}
+	}
+
+	reflectVal := reflect.ValueOf(val)
+	for i := 0; i < reflectVal.Len(); i++ {
+		if !a.item.collect(joinPath(key, strconv.Itoa(i)), reflectVal.Index(i).Interface(), v) {
+			return false
+		}
+	}
+	return true
+}
+
+//MapProperty represents a json object used as a map, whose keys are not known in advance.
+// every value in the object is validated against the same property, whose name is ignored.
+type MapProperty struct {
+	Name     string
+	propType Type
+	value    Props
+	required bool
+	nullable bool
+}
+
+//NewMapProperty creates a MapProperty whose values must be valid for the value property.
+func NewMapProperty(name string, value Props) *MapProperty {
+	return &MapProperty{
+		Name:     name,
+		propType: Group,
+		value:    value,
+	}
+}
+
+func (m MapProperty) getName() string {
+	return m.Name
+}
+
+func (m MapProperty) getType() Type {
+	return m.propType
+}
+
+func (m MapProperty) isRequired() bool {
+	return m.required
+}
+
+//Required marks the MapProperty as required. validation will fail if the key is missing
+// from the object it should be in.
+func (m *MapProperty) Required() *MapProperty {
+	m.required = true
+	return m
+}
+
+//Nullable allows the value of the MapProperty to be null.
+func (m *MapProperty) Nullable() *MapProperty {
+	m.nullable = true
+	return m
+}
+
+func (m MapProperty) validate(key string, val interface{}) error {
+	v := newValidator(1)
+	m.collect(key, val, v)
+	return v.err()
+}
+
+func (m MapProperty) collect(key string, val interface{}, v *validator) bool {
+	if val == nil {
+		if m.nullable {
+			return true
+		}
+		return v.add(typeMismatch(key, "null", "object"))
+	}
+	if reflect.TypeOf(val).Kind() != reflect.Map {
+		return v.add(typeMismatch(key, reflect.TypeOf(val).Kind().String(), "object"))
+	}
+
+	values := make(map[string]interface{})
+	keys := []string{}
+	mapIter := reflect.ValueOf(val).MapRange()
+	for mapIter.Next() {
+		keys = append(keys, mapIter.Key().String())
+		values[mapIter.Key().String()] = mapIter.Value().Interface()
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !m.value.collect(joinPath(key, k), values[k], v) {
+			return false
 		}
-	} else {
-		return fmt.Errorf("%v not a valid type. got %v want Object", key, reflect.TypeOf(val).Kind().String())
 	}
-	return nil
+	return true
 }
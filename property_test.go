@@ -1,6 +1,9 @@
 package validapi
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -148,3 +151,338 @@ func TestArrayOfProperties(t *testing.T) {
 		t.Errorf("Wanted nil got %v", err.Error())
 	}
 }
+
+func TestRequiredProperties(t *testing.T) {
+	user := NewObjectProperty("User", false).AddProperties(
+		NewProperty("Name", String).Required(),
+		NewProperty("score", Float),
+	)
+	group := NewPropertyGroup().AddProperties(
+		NewProperty("ID", Int).Required(),
+		NewProperty("status", String),
+		user.Required(),
+	)
+
+	testData := []struct {
+		name string
+		body map[string]interface{}
+		err  string
+	}{
+		{"all present", map[string]interface{}{"ID": 1, "User": map[string]interface{}{"Name": "Jimbo"}}, ""},
+		{"empty body", map[string]interface{}{}, "/ID: required property is missing; /User: required property is missing"},
+		{"missing object", map[string]interface{}{"ID": 1}, "/User: required property is missing"},
+		{"missing nested", map[string]interface{}{"ID": 1, "User": map[string]interface{}{"score": 1.2}}, "/User/Name: required property is missing"},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := group.Validate(i.body)
+			if i.err == "" && err != nil {
+				t.Errorf("wanted nil got %v", err.Error())
+			}
+			if i.err != "" && (err == nil || err.Error() != i.err) {
+				t.Errorf("wanted %v got %v", i.err, err)
+			}
+		})
+	}
+}
+
+func TestCollectAllErrors(t *testing.T) {
+	group := func() *PropertyGroup {
+		return NewPropertyGroup().AddProperties(
+			NewProperty("Name", String).Required(),
+			NewProperty("ID", Int),
+			NewObjectProperty("Users", true).AddProperties(NewProperty("Name", String)),
+		)
+	}
+	body := map[string]interface{}{
+		"ID":    "12",
+		"extra": true,
+		"Users": []interface{}{
+			map[string]interface{}{"Name": 1.0},
+			map[string]interface{}{"Name": "Jimbo"},
+			"not an object",
+		},
+	}
+
+	t.Run("all errors", func(t *testing.T) {
+		err := group().Validate(body)
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("wanted ValidationErrors got %v", err)
+		}
+		want := []struct {
+			path string
+			code string
+		}{
+			{"/ID", CodeTypeMismatch},
+			{"/Users/0/Name", CodeTypeMismatch},
+			{"/Users/2", CodeTypeMismatch},
+			{"/extra", CodeUnknownProperty},
+			{"/Name", CodeRequired},
+		}
+		if len(errs) != len(want) {
+			t.Fatalf("wanted %v errors got %v: %v", len(want), len(errs), errs.Error())
+		}
+		for i, w := range want {
+			if errs[i].Path != w.path || errs[i].Code != w.code {
+				t.Errorf("error %v: got %v %v want %v %v", i, errs[i].Path, errs[i].Code, w.path, w.code)
+			}
+		}
+	})
+
+	t.Run("max errors", func(t *testing.T) {
+		err := group().MaxErrors(2).Validate(body)
+		if errs, ok := err.(ValidationErrors); !ok || len(errs) != 2 {
+			t.Errorf("wanted 2 errors got %v", err)
+		}
+	})
+
+	t.Run("first error", func(t *testing.T) {
+		err := group().StopOnFirstError().Validate(body)
+		if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 {
+			t.Errorf("wanted 1 error got %v", err)
+		}
+	})
+}
+
+func TestValidationError(t *testing.T) {
+	enum, _ := NewEnumRule([]interface{}{"a", "b"}, String)
+	custom := NewCustomRule("NotEmpty", String, func(i interface{}) error {
+		if i.(string) == "" {
+			return errors.New("must not be empty")
+		}
+		return nil
+	})
+	group := NewPropertyGroup().AddProperties(
+		NewObjectProperty("a/b~c", false).AddProperties(NewProperty("kind", String).AddRules(enum)),
+		NewProperty("name", String).AddRules(custom),
+	)
+
+	err := group.Validate(map[string]interface{}{
+		"a/b~c": map[string]interface{}{"kind": "c"},
+		"name":  "",
+	})
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("wanted 2 ValidationErrors got %v", err)
+	}
+
+	want := []ValidationError{
+		{Path: "/a~1b~0c/kind", Code: CodeEnum, Rule: "EnumRule", Actual: "c"},
+		{Path: "/name", Code: CodeCustom, Rule: "NotEmpty", Actual: ""},
+	}
+	for i, w := range want {
+		got := errs[i]
+		if got.Path != w.Path || got.Code != w.Code || got.Rule != w.Rule || got.Actual != w.Actual {
+			t.Errorf("error %v: got %+v want %+v", i, *got, w)
+		}
+	}
+	if expected, ok := errs[0].Expected.([]interface{}); !ok || len(expected) != 2 {
+		t.Errorf("wanted enum values as expected value got %v", errs[0].Expected)
+	}
+}
+
+func TestArrayProperty(t *testing.T) {
+	tagRule, _ := NewRegexRule("^[a-z]+$")
+	tags := NewArrayProperty("tags", String).
+		AddItemRules(tagRule).
+		AddRules(NewMinItemsRule(1), NewMaxItemsRule(3), NewUniqueItemsRule())
+	group := NewPropertyGroup().AddProperties(tags, NewArrayProperty("ids", Int).AddRules(NewUniqueItemsRule()))
+
+	testData := []struct {
+		name  string
+		body  map[string]interface{}
+		paths []string
+	}{
+		{"valid", map[string]interface{}{"tags": []interface{}{"a", "b"}, "ids": []interface{}{1.0, 2.0}}, nil},
+		{"not an array", map[string]interface{}{"tags": "a"}, []string{"/tags"}},
+		{"item type", map[string]interface{}{"ids": []interface{}{1.0, "2"}}, []string{"/ids/1"}},
+		{"item rule", map[string]interface{}{"tags": []interface{}{"a", "B1"}}, []string{"/tags/1"}},
+		{"min items", map[string]interface{}{"tags": []interface{}{}}, []string{"/tags"}},
+		{"max items and unique", map[string]interface{}{"tags": []interface{}{"a", "b", "c", "a"}}, []string{"/tags", "/tags"}},
+		{"unique numbers", map[string]interface{}{"ids": []interface{}{1, 1.0}}, []string{"/ids"}},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := group.Validate(i.body)
+			if len(i.paths) == 0 {
+				if err != nil {
+					t.Errorf("wanted nil got %v", err.Error())
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || len(errs) != len(i.paths) {
+				t.Fatalf("wanted %v errors got %v", len(i.paths), err)
+			}
+			for j, path := range i.paths {
+				if errs[j].Path != path {
+					t.Errorf("got path %v want %v", errs[j].Path, path)
+				}
+			}
+		})
+	}
+}
+
+func TestIntegerProperties(t *testing.T) {
+	testData := []struct {
+		name  string
+		typ   Type
+		value interface{}
+		want  interface{}
+		code  string
+	}{
+		{"int from float", Int, 12.0, 12, ""},
+		{"int from json", Int, json.Number("1e3"), 1000, ""},
+		{"int fraction", Int, 1.5, nil, CodeTypeMismatch},
+		{"int huge", Int, 1e300, nil, CodeOutOfRange},
+		{"int json fraction", Int, json.Number("1.5"), nil, CodeTypeMismatch},
+		{"int64 precision", Int64, json.Number("9007199254740993"), int64(9007199254740993), ""},
+		{"int64 overflow", Int64, json.Number("9223372036854775808"), nil, CodeOutOfRange},
+		{"int32", Int32, 2147483647.0, int32(2147483647), ""},
+		{"int32 overflow", Int32, 2147483648.0, nil, CodeOutOfRange},
+		{"uint", Uint, json.Number("18446744073709551615"), uint(18446744073709551615), ""},
+		{"uint negative", Uint, -1.0, nil, CodeOutOfRange},
+		{"float from json", Float, json.Number("1.25"), 1.25, ""},
+		{"not a number", Int, "12", nil, CodeTypeMismatch},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			var got interface{}
+			rule := NewCustomRule("capture", i.typ, func(v interface{}) error {
+				got = v
+				return nil
+			})
+			err := NewProperty("n", i.typ).AddRules(rule).validate("/n", i.value)
+			if i.code == "" {
+				if err != nil {
+					t.Fatalf("wanted nil got %v", err.Error())
+				}
+				if got != i.want {
+					t.Errorf("rule received %v (%T) want %v (%T)", got, got, i.want, i.want)
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || errs[0].Code != i.code {
+				t.Errorf("wanted %v error got %v", i.code, err)
+			}
+		})
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	group := NewPropertyGroup().AddProperties(
+		NewProperty("Name", String).Required(),
+		NewProperty("ID", Int64),
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		body, err := group.ValidateJSON([]byte(`{"Name": "Jimbo", "ID": 9007199254740993}`))
+		if err != nil {
+			t.Fatalf("wanted nil got %v", err.Error())
+		}
+		if body["ID"] != json.Number("9007199254740993") {
+			t.Errorf("number lost precision. got %v", body["ID"])
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := group.ValidateJSON([]byte(`{"ID": 1}`))
+		if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Code != CodeRequired {
+			t.Errorf("wanted a required error got %v", err)
+		}
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		for _, data := range []string{`[]`, `null`, `"Name"`} {
+			if _, err := group.ValidateJSON([]byte(data)); err != ErrNotJSONObject {
+				t.Errorf("%v: wanted ErrNotJSONObject got %v", data, err)
+			}
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		for _, data := range []string{``, `Name`, `{bad`, `{"Name": "Jimbo"} {"junk": true} garbage`, `{"Name": "Jimbo"} x`} {
+			body, err := group.ValidateJSON([]byte(data))
+			if err == nil || err == ErrNotJSONObject || body != nil {
+				t.Errorf("%q: wanted a json error got %v", data, err)
+			}
+		}
+
+		var syntaxErr *json.SyntaxError
+		if _, err := group.ValidateJSON([]byte(`{bad`)); !errors.As(err, &syntaxErr) {
+			t.Errorf("wanted the syntax error to be wrapped. got %v", err)
+		}
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		if _, err := group.ValidateReader(strings.NewReader(`{"Name": "Jimbo"}`)); err != nil {
+			t.Errorf("wanted nil got %v", err.Error())
+		}
+	})
+}
+
+func TestMapProperty(t *testing.T) {
+	group := NewPropertyGroup().AddProperties(
+		NewMapProperty("Labels", NewProperty("", String)).Nullable(),
+		NewProperty("Nick", String).Nullable(),
+	)
+
+	testData := []struct {
+		name string
+		body map[string]interface{}
+		path string
+	}{
+		{"valid", map[string]interface{}{"Labels": map[string]interface{}{"env": "prod", "team": "api"}}, ""},
+		{"null", map[string]interface{}{"Labels": nil, "Nick": nil}, ""},
+		{"invalid value", map[string]interface{}{"Labels": map[string]interface{}{"a/b": 1.0}}, "/Labels/a~1b"},
+		{"not an object", map[string]interface{}{"Labels": "env"}, "/Labels"},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := group.Validate(i.body)
+			if i.path == "" {
+				if err != nil {
+					t.Errorf("wanted nil got %v", err.Error())
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || errs[0].Path != i.path {
+				t.Errorf("wanted error at %v got %v", i.path, err)
+			}
+		})
+	}
+}
+
+func TestArrayPropertyUniqueJSON(t *testing.T) {
+	group := NewPropertyGroup().AddProperties(NewArrayProperty("ids", Int).AddRules(NewUniqueItemsRule()))
+	_, err := group.ValidateJSON([]byte(`{"ids": [1, 1.0]}`))
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Code != CodeUniqueItems {
+		t.Errorf("wanted a unique items error got %v", err)
+	}
+}
+
+func TestObjectPropertyRules(t *testing.T) {
+	users := NewObjectProperty("users", true).
+		AddProperties(NewProperty("name", String)).
+		AddRules(NewMinItemsRule(1))
+	group := NewPropertyGroup().AddProperties(users)
+
+	err := group.Validate(map[string]interface{}{"users": []interface{}{}})
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Code != CodeMinItems {
+		t.Errorf("wanted a min items error got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("wanted panic got nil")
+		}
+	}()
+	NewObjectProperty("user", false).AddRules(NewMinItemsRule(1))
+}
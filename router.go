@@ -0,0 +1,195 @@
+package validapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//methods the http methods a handler is registered for by MountHandler.
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+//Endpoint is returned when a handler is registered on a Router. it is used to attach
+// a PropertyGroup and typed path parameters to the handler.
+type Endpoint struct {
+	leaf *leaf
+}
+
+//Body sets the PropertyGroup the request body will be validated against before the
+// handler is called. Passing nil removes body validation from the endpoint.
+func (e *Endpoint) Body(pg *PropertyGroup) *Endpoint {
+	e.leaf.group = pg
+	return e
+}
+
+//Params declares the Type and Rules of path parameters using Properties named after the
+// parameters in the path pattern. the raw path segment is converted to the property's Type
+// and validated before the handler is called. requests that fail receive a 400 response.
+// will panic if a property does not match a parameter in the pattern.
+func (e *Endpoint) Params(props ...*Property) *Endpoint {
+	names := paramNames(e.leaf.pattern)
+	for _, prop := range props {
+		if !containsString(names, prop.Name) {
+			panic(fmt.Errorf("parameter %v is not in path %v", prop.Name, e.leaf.pattern))
+		}
+		if e.leaf.params == nil {
+			e.leaf.params = make(map[string]*Property)
+		}
+		e.leaf.params[prop.Name] = prop
+	}
+	return e
+}
+
+//Use adds middleware to the endpoint. it runs after the middleware of every Router the
+// request passed through, in the order it was added.
+func (e *Endpoint) Use(mw ...Middleware) *Endpoint {
+	e.leaf.middleware = append(e.leaf.middleware, mw...)
+	return e
+}
+
+//NewRouter creates an empty Router that can be mounted on a ValidAPI or another Router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+//Use adds middleware to the router. it runs for every route registered on the router or
+// on routers mounted below it, after the middleware of the routers above it.
+func (r *Router) Use(mw ...Middleware) *Router {
+	r.middleware = append(r.middleware, mw...)
+	return r
+}
+
+//Mount places the sub router at the prefix, which must only contain static segments.
+// routes registered on the sub router are relative to the prefix. will panic if the
+// prefix is empty, contains parameters, is already used by another route, or a route of
+// the sub router is shadowed by a catch-all of the router.
+func (r *Router) Mount(prefix string, sub *Router) *Router {
+	if err := r.AddRouter(prefix, sub); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+//AddRouter works like Mount, but returns an error instead of panicking when the sub router
+// cannot be mounted. the router is left unchanged when an error is returned. routes added
+// to the sub router after it is mounted are only checked against the sub router.
+func (r *Router) AddRouter(prefix string, sub *Router) error {
+	segs := splitPath(prefix)
+	if len(segs) == 0 {
+		return fmt.Errorf("cannot mount a router at the root path")
+	}
+	for _, seg := range segs {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			return fmt.Errorf("cannot mount a router at %v. prefix must only contain static segments", prefix)
+		}
+	}
+
+	last := segs[len(segs)-1]
+	if parent := r.staticRoute(segs[:len(segs)-1]); parent != nil {
+		if _, present := parent.staticChildren[last]; present {
+			return fmt.Errorf("cannot mount a router at %v. path is already in use", prefix)
+		}
+		//routes of the sub router end at the prefix only when they are registered on its root,
+		// so those are the only routes a catch-all next to the prefix can shadow.
+		for method, l := range sub.handlers {
+			if existing, ok := parent.defaultChild.leafFor(method); ok {
+				return fmt.Errorf("cannot mount a router at %v. route %v %v is shadowed by catch-all route %v", prefix, method, l.pattern, existing.pattern)
+			}
+		}
+	}
+
+	parent, err := r.add(segs[:len(segs)-1], "")
+	if err != nil {
+		return fmt.Errorf("cannot mount a router at %v. error: %v", prefix, err)
+	}
+	if parent.staticChildren == nil {
+		parent.staticChildren = make(map[string]node)
+	}
+	sub.Path = last
+	parent.staticChildren[last] = sub
+	return nil
+}
+
+//MountHandler serves every request below the prefix, for any method, with the handler.
+// the request path is passed on unchanged, so handlers expecting a path relative to the
+// prefix should be wrapped with http.StripPrefix.
+func (r *Router) MountHandler(prefix string, h http.Handler) *Router {
+	pattern := strings.TrimRight(prefix, "/") + "/*path"
+	for _, method := range methods {
+		r.Handle(method, pattern, h.ServeHTTP)
+	}
+	return r
+}
+
+//Handle registers the handler for the provided method and path pattern. segments starting
+// with ':' match any single segment, and a final segment starting with '*' matches the rest
+// of the path. will panic if the route conflicts with a route that is already registered.
+func (r *Router) Handle(method, pattern string, h http.HandlerFunc) *Endpoint {
+	e, err := r.AddRoute(method, pattern, h)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+//AddRoute works like Handle, but returns an error instead of panicking when the route
+// conflicts with one that is already registered. a route conflicts when the method and path
+// are already registered, when a parameter has a different name than the parameter at the
+// same position of another route, or when a catch-all and a static or parameter route
+// for the same method end at the same position.
+func (r *Router) AddRoute(method, pattern string, h http.HandlerFunc) (*Endpoint, error) {
+	rt, err := r.add(splitPath(pattern), method)
+	if err != nil {
+		return nil, fmt.Errorf("could not add route %v %v. error: %v", method, pattern, err)
+	}
+	if existing, ok := rt.handlers[method]; ok {
+		return nil, fmt.Errorf("could not add route %v %v. error: conflicts with route %v %v", method, pattern, method, existing.pattern)
+	}
+	l := &leaf{pattern: pattern, handler: h}
+	rt.handle(method, l)
+	return &Endpoint{leaf: l}, nil
+}
+
+//Get registers a handler for GET requests to the path pattern.
+func (r *Router) Get(pattern string, h http.HandlerFunc) *Endpoint {
+	return r.Handle(http.MethodGet, pattern, h)
+}
+
+//Post registers a handler for POST requests to the path pattern.
+func (r *Router) Post(pattern string, h http.HandlerFunc) *Endpoint {
+	return r.Handle(http.MethodPost, pattern, h)
+}
+
+//Put registers a handler for PUT requests to the path pattern.
+func (r *Router) Put(pattern string, h http.HandlerFunc) *Endpoint {
+	return r.Handle(http.MethodPut, pattern, h)
+}
+
+//Patch registers a handler for PATCH requests to the path pattern.
+func (r *Router) Patch(pattern string, h http.HandlerFunc) *Endpoint {
+	return r.Handle(http.MethodPatch, pattern, h)
+}
+
+//Delete registers a handler for DELETE requests to the path pattern.
+func (r *Router) Delete(pattern string, h http.HandlerFunc) *Endpoint {
+	return r.Handle(http.MethodDelete, pattern, h)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package validapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegisterEndpoints(t *testing.T) {
+	api := New()
+	var got map[string]interface{}
+	create := func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}
+	list := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	api.Post("/users", create).Body(NewPropertyGroup().AddProperties(NewProperty("Name", String)))
+	api.Get("/users", list)
+
+	t.Run("valid body reaches handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"Name": "Jimbo"}`)))
+		if rec.Code != http.StatusCreated {
+			t.Errorf("got status %v want %v", rec.Code, http.StatusCreated)
+		}
+		if got["Name"] != "Jimbo" {
+			t.Errorf("handler could not read body. got %v", got)
+		}
+	})
+
+	t.Run("invalid body is rejected", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"ID": 1}`)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("got status %v want %v", rec.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("methods are registered separately", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("got status %v want %v", rec.Code, http.StatusOK)
+		}
+	})
+}
+
+func TestMount(t *testing.T) {
+	order := ""
+	tag := func(s string) Middleware {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				order += s
+				next(w, r)
+			}
+		}
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) { order += "h" }
+
+	api := New()
+	api.Use(tag("a"))
+	admin := NewRouter().Use(tag("b"))
+	admin.Get("/users/:id", handler).Use(tag("c"))
+	api.Mount("/v1/admin", admin)
+	api.Get("/v1/public", handler)
+
+	testData := []struct {
+		path string
+		want string
+	}{
+		{"/v1/admin/users/12", "abch"},
+		{"/v1/public", "ah"},
+	}
+
+	for _, i := range testData {
+		order = ""
+		api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, i.path, nil))
+		if order != i.want {
+			t.Errorf("%v: got order %v want %v", i.path, order, i.want)
+		}
+	}
+}
+
+func TestMountConflicts(t *testing.T) {
+	testData := []struct {
+		name  string
+		setup func(api *ValidAPI)
+	}{
+		{"root", func(api *ValidAPI) { api.Mount("/", NewRouter()) }},
+		{"parameter", func(api *ValidAPI) { api.Mount("/users/:id", NewRouter()) }},
+		{"in use", func(api *ValidAPI) {
+			api.Get("/admin", nil)
+			api.Mount("/admin", NewRouter())
+		}},
+		{"shadowed by catch-all", func(api *ValidAPI) {
+			api.Get("/*all", nil)
+			sub := NewRouter()
+			sub.Get("/", nil)
+			api.Mount("/admin", sub)
+		}},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("wanted panic got nil")
+				}
+			}()
+			i.setup(New())
+		})
+	}
+}
+
+func TestAddRouter(t *testing.T) {
+	api := New()
+	api.Get("/v1/*all", nil)
+	sub := NewRouter()
+	sub.Get("/", nil)
+
+	if err := api.AddRouter("/v1/admin", sub); err == nil || !strings.Contains(err.Error(), "/v1/*all") {
+		t.Fatalf("wanted an error naming the catch-all got %v", err)
+	}
+	if err := api.AddRouter("/v2/admin", sub); err != nil {
+		t.Fatalf("wanted nil got %v", err.Error())
+	}
+
+	t.Run("failed mount leaves the tree", func(t *testing.T) {
+		api := New()
+		api.Get("/a/*all", nil)
+		if err := api.AddRouter("/a/admin", sub); err == nil {
+			t.Fatal("wanted error got nil")
+		}
+		if api.staticRoute([]string{"a", "admin"}) != nil {
+			t.Error("failed mount should not add the prefix")
+		}
+		if err := api.AddRouter("/a/admin", NewRouter()); err != nil {
+			t.Errorf("wanted nil got %v", err.Error())
+		}
+	})
+
+	t.Run("routes below the prefix", func(t *testing.T) {
+		api := New()
+		api.Get("/*all", nil)
+		admin := NewRouter()
+		admin.Get("/users", nil)
+		if err := api.AddRouter("/admin", admin); err != nil {
+			t.Errorf("wanted nil got %v", err.Error())
+		}
+	})
+}
+
+func TestMountHandler(t *testing.T) {
+	api := New()
+	var got string
+	api.MountHandler("/debug/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.Path
+	}))
+
+	for _, i := range []struct{ method, path string }{
+		{http.MethodGet, "/debug"},
+		{http.MethodPost, "/debug/pprof/profile"},
+	} {
+		api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(i.method, i.path, nil))
+		if want := i.method + " " + i.path; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+}
+
+func TestIntegerBody(t *testing.T) {
+	api := New()
+	api.Post("/users", func(w http.ResponseWriter, r *http.Request) {}).Body(NewPropertyGroup().AddProperties(
+		NewProperty("ID", Int64),
+	))
+
+	testData := []struct {
+		body string
+		want int
+	}{
+		{`{"ID": 9223372036854775807}`, http.StatusOK},
+		{`{"ID": 1.5}`, http.StatusBadRequest},
+		{`{"ID": 1e300}`, http.StatusBadRequest},
+	}
+
+	for _, i := range testData {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(i.body)))
+		if rec.Code != i.want {
+			t.Errorf("%v: got status %v want %v", i.body, rec.Code, i.want)
+		}
+	}
+}
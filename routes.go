@@ -0,0 +1,85 @@
+package validapi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+//RouteInfo describes a single handler registered on the tree.
+type RouteInfo struct {
+	Method  string
+	Pattern string
+	Params  []ParamInfo
+	//Middleware the number of middleware functions the request runs through, counting both
+	// the middleware of the Routers above the route and the middleware of the route itself.
+	Middleware int
+	//Body the PropertyGroup the request body is validated against. nil if there is none.
+	Body *PropertyGroup
+}
+
+//ParamInfo describes a path parameter of a route. parameters that were not declared with
+// a Property on the Endpoint have the String Type.
+type ParamInfo struct {
+	Name string
+	Type Type
+}
+
+//info builds the RouteInfo of the leaf from the full pattern it was found at.
+func (l *leaf) info(method, pattern string, mw int) RouteInfo {
+	info := RouteInfo{
+		Method:     method,
+		Pattern:    pattern,
+		Params:     []ParamInfo{},
+		Middleware: mw + len(l.middleware),
+		Body:       l.group,
+	}
+	for _, name := range paramNames(pattern) {
+		param := ParamInfo{Name: name, Type: String}
+		if prop, ok := l.params[name]; ok {
+			param.Type = prop.getType()
+		}
+		info.Params = append(info.Params, param)
+	}
+	return info
+}
+
+//Walk calls fn for every handler registered on the router and the routers mounted below it,
+// sorted by path and then method. patterns are relative to the router, even when it is
+// mounted on another router. if fn returns an error, the walk stops and the error is returned.
+func (r *Router) Walk(fn func(RouteInfo) error) error {
+	return r.Route.walkAt("/", len(r.middleware), fn)
+}
+
+//Routes returns the RouteInfo of every handler registered on the router, in the order
+// they are visited by Walk.
+func (r *Router) Routes() []RouteInfo {
+	routes := []RouteInfo{}
+	r.Walk(func(info RouteInfo) error {
+		routes = append(routes, info)
+		return nil
+	})
+	return routes
+}
+
+//PrintRoutes writes an aligned table of the routes registered on the router to w.
+func (r *Router) PrintRoutes(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATTERN\tPARAMS\tMIDDLEWARE\tBODY")
+	for _, route := range r.Routes() {
+		params := make([]string, 0, len(route.Params))
+		for _, p := range route.Params {
+			params = append(params, p.Name+":"+p.Type.String())
+		}
+		if len(params) == 0 {
+			params = append(params, "-")
+		}
+		body := "-"
+		if route.Body != nil {
+			body = fmt.Sprintf("%v properties", len(route.Body.properties))
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", route.Method, route.Pattern, strings.Join(params, ","), route.Middleware, body)
+	}
+	return tw.Flush()
+}
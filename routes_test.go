@@ -0,0 +1,90 @@
+package validapi
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	mw := func(next http.HandlerFunc) http.HandlerFunc { return next }
+	group := NewPropertyGroup().AddProperties(NewProperty("Name", String))
+
+	api := New()
+	api.Use(mw)
+	api.Get("/", nil)
+	api.Post("/users", nil).Body(group)
+	api.Get("/users", nil)
+	admin := NewRouter().Use(mw)
+	admin.Get("/users/:id", nil).Params(NewProperty("id", Int)).Use(mw)
+	api.Mount("/admin", admin)
+
+	routes := api.Routes()
+	want := []struct {
+		method     string
+		pattern    string
+		middleware int
+	}{
+		{http.MethodGet, "/", 1},
+		{http.MethodGet, "/admin/users/:id", 3},
+		{http.MethodGet, "/users", 1},
+		{http.MethodPost, "/users", 1},
+	}
+	if len(routes) != len(want) {
+		t.Fatalf("got %v routes want %v", len(routes), len(want))
+	}
+	for i, w := range want {
+		got := routes[i]
+		if got.Method != w.method || got.Pattern != w.pattern || got.Middleware != w.middleware {
+			t.Errorf("route %v: got %v %v %v want %v %v %v", i, got.Method, got.Pattern, got.Middleware, w.method, w.pattern, w.middleware)
+		}
+	}
+
+	if params := routes[1].Params; len(params) != 1 || params[0].Name != "id" || params[0].Type != Int {
+		t.Errorf("got params %v want id:int", params)
+	}
+	if routes[3].Body != group {
+		t.Error("wanted POST /users to have the property group")
+	}
+}
+
+func TestRoutesOfMountedRouter(t *testing.T) {
+	mw := func(next http.HandlerFunc) http.HandlerFunc { return next }
+	admin := NewRouter().Use(mw)
+	admin.Get("/", nil)
+	admin.Get("/x", nil)
+	New().Mount("/v1/admin", admin)
+
+	routes := admin.Routes()
+	want := []string{"/", "/x"}
+	if len(routes) != len(want) {
+		t.Fatalf("got %v routes want %v", len(routes), len(want))
+	}
+	for i, pattern := range want {
+		if routes[i].Pattern != pattern || routes[i].Middleware != 1 {
+			t.Errorf("route %v: got %v %v want %v 1", i, routes[i].Pattern, routes[i].Middleware, pattern)
+		}
+	}
+}
+
+func TestPrintRoutes(t *testing.T) {
+	api := New()
+	api.Get("/users/:id", nil)
+	api.Post("/users", nil)
+
+	var buf bytes.Buffer
+	if err := api.PrintRoutes(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("wanted header and 2 routes got %v", buf.String())
+	}
+	if !strings.Contains(lines[2], "/users/:id") || !strings.Contains(lines[2], "id:string") {
+		t.Errorf("unexpected route line %q", lines[2])
+	}
+	if strings.Index(lines[0], "PATTERN") != strings.Index(lines[1], "/users") {
+		t.Errorf("columns are not aligned:\n%v", buf.String())
+	}
+}
@@ -1,15 +1,23 @@
 package validapi
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 //Rule Interface that defines the common interfaced that should be used when
 // implementing a rule type all implemented rules can assume that the provided value
 // is already an appropriate type, because the value will have been type checked before
-// and invalid ones will not make it to the rule.
+// and invalid ones will not make it to the rule. rules may return a *ValidationError
+// to report their own error code and expected value.
 // NOTE: rules cannot be applied to the Object Property Type.
 type Rule interface {
 	//validate the function used to define if an input is valid or not.
@@ -22,39 +30,111 @@ type Rule interface {
 	rulevalidation(Props) error
 }
 
+//DescribedRule is implemented by rules that expose their parameters, keyed by the matching
+// JSON Schema keyword, for use in documentation and schema export.
+type DescribedRule interface {
+	Rule
+	Params() map[string]interface{}
+}
+
 //RegexRule checks to see if the  propety value of a property matches the provided regex string.
+// the pattern is compiled once, when the rule is created.
 type RegexRule struct {
-	regexStr string
+	regexStr  string
+	flags     string
+	fullMatch bool
+	preset    string
+	message   string
+	regex     *regexp.Regexp
 }
 
 //NewRegexRule accepts a string, confirms it's a valid regex pattern, and returns a rule if the pattern is
 //valid. If it is not, it will return a blank rule and an error noting that the regex is invalid.
 func NewRegexRule(str string) (RegexRule, error) {
+	return NewRegexRuleWithFlags(str, "")
+}
 
-	_, err := regexp.Compile(str)
-	if err != nil {
+//NewRegexRuleWithFlags works like NewRegexRule, but sets go regexp flags on the whole pattern.
+// the flags are i (case insensitive), m (multi-line), s (. matches \n) and U (ungreedy).
+func NewRegexRuleWithFlags(str, flags string) (RegexRule, error) {
+	for _, f := range flags {
+		if !strings.ContainsRune("imsU", f) {
+			return RegexRule{}, fmt.Errorf("unknown regex flag %q", f)
+		}
+	}
+	r := RegexRule{regexStr: str, flags: flags}
+	if err := r.compile(); err != nil {
 		return RegexRule{}, err
 	}
+	return r, nil
+}
 
-	return RegexRule{
-		regexStr: str,
-	}, nil
+//FullMatch returns a copy of the rule that only passes when the pattern matches the whole
+// value, without having to anchor the pattern with ^ and $. the whole value must match even
+// with the m flag, which makes ^ and $ match at line boundaries.
+func (r RegexRule) FullMatch() RegexRule {
+	r.fullMatch = true
+	//Note: we ignore the error because the pattern already compiled without the anchors.
+	_ = r.compile()
+	return r
+}
+
+//SetMessage sets the message of the errors the rule returns. when set, the pattern is
+// left out of the error, so it is not sent to clients.
+func (r *RegexRule) SetMessage(msg string) {
+	r.message = msg
+}
+
+//compile builds the regexp from the pattern, flags and match mode of the rule.
+func (r *RegexRule) compile() error {
+	expr := r.regexStr
+	if r.fullMatch {
+		expr = `\A(?:` + expr + `)\z`
+	}
+	if r.flags != "" {
+		expr = "(?" + r.flags + ")" + expr
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+	r.regex = regex
+	return nil
 }
 
 func (r RegexRule) validate(i interface{}) error {
 
 	value := i.(string)
-	//Note: we ignore the error because it should have already been
-	// confirmed that the expression string compiles when it was created.
-	regex, _ := regexp.Compile(r.regexStr)
-	if regex.MatchString(value) {
+	if r.regex.MatchString(value) {
 		return nil
 	}
-	return fmt.Errorf("%v does not match regex pattern %v", value, r.regexStr)
+	switch {
+	case r.message != "":
+		return &ValidationError{Code: CodeRegex, Message: r.message}
+	case r.preset != "":
+		return &ValidationError{
+			Code:     CodeRegex,
+			Message:  fmt.Sprintf("not a valid %v", r.preset),
+			Expected: r.preset,
+		}
+	}
+	return &ValidationError{
+		Code:     CodeRegex,
+		Message:  fmt.Sprintf("%v does not match regex pattern %v", value, r.regex.String()),
+		Expected: r.regex.String(),
+	}
 
 }
 
+//Params returns the pattern of the rule, including the anchors and flags it was built with.
+func (r RegexRule) Params() map[string]interface{} {
+	return map[string]interface{}{"pattern": r.regex.String()}
+}
+
 func (r RegexRule) rulevalidation(p Props) error {
+	if r.regex == nil {
+		return fmt.Errorf("regex rule has no pattern. it should be created with NewRegexRule")
+	}
 	if p.getType() != String {
 		err := fmt.Errorf("regex rule cannot be used with property. got type %v, need string", p.getType().String())
 		return err
@@ -62,41 +142,138 @@ func (r RegexRule) rulevalidation(p Props) error {
 	return nil
 }
 
+var (
+	regexPresetsMu sync.RWMutex
+	regexPresets   = map[string]string{
+		"alpha":        "^[a-zA-Z]+$",
+		"alphanumeric": "^[a-zA-Z0-9]+$",
+		"numeric":      "^[0-9]+$",
+		"hex":          "^[0-9a-fA-F]+$",
+		"slug":         "^[a-z0-9]+(?:-[a-z0-9]+)*$",
+	}
+)
+
+//RegisterRegexPreset adds a named pattern that can be used with NewRegexPreset. will panic
+// if the pattern does not compile or the name is already registered.
+func RegisterRegexPreset(name, pattern string) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		panic(fmt.Errorf("invalid pattern for regex preset %v. error: %v", name, err))
+	}
+	regexPresetsMu.Lock()
+	defer regexPresetsMu.Unlock()
+	if _, present := regexPresets[name]; present {
+		panic(fmt.Errorf("duplicated regex preset name: %v", name))
+	}
+	regexPresets[name] = pattern
+}
+
+//NewRegexPreset creates a RegexRule from a named pattern. the built in presets are alpha,
+// alphanumeric, numeric, hex and slug, and more can be added with RegisterRegexPreset.
+// errors name the preset instead of showing the pattern. it returns a blank rule and an
+// error if the preset is not registered.
+func NewRegexPreset(name string) (RegexRule, error) {
+	regexPresetsMu.RLock()
+	pattern, ok := regexPresets[name]
+	regexPresetsMu.RUnlock()
+	if !ok {
+		return RegexRule{}, fmt.Errorf("unknown regex preset %v", name)
+	}
+	r, err := NewRegexRule(pattern)
+	if err != nil {
+		return RegexRule{}, err
+	}
+	r.preset = name
+	return r, nil
+}
+
 //EnumRule checks to see if the property value is within a set of valid values.
+// numbers are compared by value, so 1, 1.0 and json.Number("1") are the same member.
 type EnumRule struct {
 	enumType   Type
-	enumvalues map[interface{}]struct{}
+	members    []interface{}
+	ignoreCase bool
+	enumvalues map[string]struct{}
 }
 
 //NewEnumRule checks to see if all members provided are the same type,
 // if so, it will return a valid EnumRule. if not, it will return
-// a blank rule and a error. type is inferred by checking the first member of the array.
+// a blank rule and a error. members of numeric enums can be any number
+// that converts to the enum type without loss.
 func NewEnumRule(members []interface{}, t Type) (EnumRule, error) {
 
 	enumType := t
-	enumvalues := make(map[interface{}]struct{})
-	var empty struct{}
 	for _, val := range members {
-		if reflect.TypeOf(val) != enumType {
+		if isNumeric(enumType) {
+			if _, err := convertNumber("", val, enumType); err != nil {
+				return EnumRule{}, fmt.Errorf("enum type mismatch. %v", err.Message)
+			}
+		} else if reflect.TypeOf(val) != enumType {
 			err := fmt.Errorf("enum type mismatch")
 			return EnumRule{}, err
 		}
-		enumvalues[val] = empty
 	}
-	return EnumRule{
-		enumType:   enumType,
-		enumvalues: enumvalues,
-	}, nil
+	r := EnumRule{
+		enumType: enumType,
+		members:  members,
+	}
+	r.buildValues()
+	return r, nil
+}
+
+//IgnoreCase returns a copy of the rule that compares strings case insensitively.
+// it has no effect on enums that are not strings.
+func (r EnumRule) IgnoreCase() EnumRule {
+	r.ignoreCase = true
+	r.buildValues()
+	return r
+}
+
+//buildValues fills the lookup map with the key of every member.
+func (r *EnumRule) buildValues() {
+	r.enumvalues = make(map[string]struct{})
+	for _, val := range r.members {
+		r.enumvalues[r.key(val)] = struct{}{}
+	}
+}
+
+//key returns the lookup key of a value. numbers that are equal have the same key
+// regardless of their go type, and the kind of value is part of the key so "1" and 1
+// never match.
+func (r EnumRule) key(i interface{}) string {
+	if n, ok := numberKey(i); ok {
+		return "n:" + n
+	}
+	if str, ok := i.(string); ok {
+		if r.ignoreCase {
+			str = strings.ToLower(str)
+		}
+		return "s:" + str
+	}
+	return fmt.Sprintf("%T:%v", i, i)
 }
 
 func (r EnumRule) validate(i interface{}) error {
-	if _, ok := r.enumvalues[i]; ok {
+	if _, ok := r.enumvalues[r.key(i)]; ok {
 		return nil
 	}
 
-	return fmt.Errorf("%v not in enum list", i)
+	allowed := make([]string, 0, len(r.members))
+	for _, member := range r.members {
+		allowed = append(allowed, fmt.Sprintf("%v", member))
+	}
+	return &ValidationError{
+		Code:     CodeEnum,
+		Message:  fmt.Sprintf("%v not in enum list. want one of %v", i, strings.Join(allowed, ", ")),
+		Expected: r.members,
+	}
+
+}
 
+//Params returns the values of the enum.
+func (r EnumRule) Params() map[string]interface{} {
+	return map[string]interface{}{"enum": r.members}
 }
+
 func (r EnumRule) rulevalidation(p Props) error {
 
 	if r.enumType != p.getType() {
@@ -138,3 +315,456 @@ func (cr CustomRule) rulevalidation(p Props) error {
 	}
 	return nil
 }
+
+//MinItemsRule checks that an array has at least a minimum number of elements.
+// it can only be used with ArrayProperty.
+type MinItemsRule struct {
+	min int
+}
+
+//NewMinItemsRule creates a MinItemsRule that requires at least min elements.
+func NewMinItemsRule(min int) MinItemsRule {
+	return MinItemsRule{min: min}
+}
+
+func (r MinItemsRule) validate(i interface{}) error {
+	if n := reflect.ValueOf(i).Len(); n < r.min {
+		return &ValidationError{
+			Code:     CodeMinItems,
+			Message:  fmt.Sprintf("has %v items, want at least %v", n, r.min),
+			Expected: r.min,
+		}
+	}
+	return nil
+}
+
+//Params returns the minimum number of items.
+func (r MinItemsRule) Params() map[string]interface{} {
+	return map[string]interface{}{"minItems": r.min}
+}
+
+func (r MinItemsRule) rulevalidation(p Props) error {
+	return arrayRuleValidation("min items", p)
+}
+
+//MaxItemsRule checks that an array has at most a maximum number of elements.
+// it can only be used with ArrayProperty.
+type MaxItemsRule struct {
+	max int
+}
+
+//NewMaxItemsRule creates a MaxItemsRule that allows at most max elements.
+func NewMaxItemsRule(max int) MaxItemsRule {
+	return MaxItemsRule{max: max}
+}
+
+func (r MaxItemsRule) validate(i interface{}) error {
+	if n := reflect.ValueOf(i).Len(); n > r.max {
+		return &ValidationError{
+			Code:     CodeMaxItems,
+			Message:  fmt.Sprintf("has %v items, want at most %v", n, r.max),
+			Expected: r.max,
+		}
+	}
+	return nil
+}
+
+//Params returns the maximum number of items.
+func (r MaxItemsRule) Params() map[string]interface{} {
+	return map[string]interface{}{"maxItems": r.max}
+}
+
+func (r MaxItemsRule) rulevalidation(p Props) error {
+	return arrayRuleValidation("max items", p)
+}
+
+//UniqueItemsRule checks that every element of an array is different.
+// it can only be used with ArrayProperty.
+type UniqueItemsRule struct{}
+
+//NewUniqueItemsRule creates a UniqueItemsRule.
+func NewUniqueItemsRule() UniqueItemsRule {
+	return UniqueItemsRule{}
+}
+
+//uniqueNumber the key of a number in UniqueItemsRule, so numbers never equal strings.
+type uniqueNumber string
+
+//validate looks up comparable elements in a map, so only elements such as objects and
+// nested arrays are compared one by one. numbers are compared by value, so 1, 1.0 and
+// json.Number("1.0") are equal.
+func (r UniqueItemsRule) validate(i interface{}) error {
+	val := reflect.ValueOf(i)
+	seen := make(map[interface{}]int)
+	uncomparable := []int{}
+	for a := 0; a < val.Len(); a++ {
+		item := val.Index(a).Interface()
+		if n, ok := numberKey(item); ok {
+			item = uniqueNumber(n)
+		}
+		b, found := -1, false
+		if item == nil || reflect.TypeOf(item).Comparable() {
+			b, found = seen[item]
+			seen[item] = a
+		} else {
+			for _, j := range uncomparable {
+				if reflect.DeepEqual(item, val.Index(j).Interface()) {
+					b, found = j, true
+					break
+				}
+			}
+			uncomparable = append(uncomparable, a)
+		}
+		if found {
+			return &ValidationError{
+				Code:    CodeUniqueItems,
+				Message: fmt.Sprintf("items %v and %v are equal", b, a),
+			}
+		}
+	}
+	return nil
+}
+
+//Params returns the uniqueItems keyword.
+func (r UniqueItemsRule) Params() map[string]interface{} {
+	return map[string]interface{}{"uniqueItems": true}
+}
+
+func (r UniqueItemsRule) rulevalidation(p Props) error {
+	return arrayRuleValidation("unique items", p)
+}
+
+//arrayRuleValidation checks that a rule meant for arrays is being added to an array property.
+func arrayRuleValidation(name string, p Props) error {
+	if p.getType() != Array {
+		return fmt.Errorf("%v rule cannot be used with property. got type %v, need array", name, p.getType().String())
+	}
+	return nil
+}
+
+//MinRule checks that a number is at least a minimum. if the rule is exclusive, the number
+// must be greater than the minimum. it can be used with any numeric property.
+type MinRule struct {
+	min       float64
+	exclusive bool
+}
+
+//NewMinRule creates a MinRule that allows numbers greater than or equal to min.
+func NewMinRule(min float64) MinRule {
+	return MinRule{min: min}
+}
+
+//NewExclusiveMinRule creates a MinRule that allows numbers greater than min.
+func NewExclusiveMinRule(min float64) MinRule {
+	return MinRule{min: min, exclusive: true}
+}
+
+func (r MinRule) validate(i interface{}) error {
+	c := compareNumber(i, r.min)
+	if c < 0 || (r.exclusive && c == 0) {
+		want := "at least"
+		if r.exclusive {
+			want = "greater than"
+		}
+		return &ValidationError{
+			Code:     CodeMinimum,
+			Message:  fmt.Sprintf("%v is not %v %v", i, want, r.min),
+			Expected: r.min,
+		}
+	}
+	return nil
+}
+
+//Params returns the minimum, keyed by minimum or exclusiveMinimum.
+func (r MinRule) Params() map[string]interface{} {
+	if r.exclusive {
+		return map[string]interface{}{"exclusiveMinimum": r.min}
+	}
+	return map[string]interface{}{"minimum": r.min}
+}
+
+func (r MinRule) rulevalidation(p Props) error {
+	return numericRuleValidation("min", p)
+}
+
+//MaxRule checks that a number is at most a maximum. if the rule is exclusive, the number
+// must be less than the maximum. it can be used with any numeric property.
+type MaxRule struct {
+	max       float64
+	exclusive bool
+}
+
+//NewMaxRule creates a MaxRule that allows numbers less than or equal to max.
+func NewMaxRule(max float64) MaxRule {
+	return MaxRule{max: max}
+}
+
+//NewExclusiveMaxRule creates a MaxRule that allows numbers less than max.
+func NewExclusiveMaxRule(max float64) MaxRule {
+	return MaxRule{max: max, exclusive: true}
+}
+
+func (r MaxRule) validate(i interface{}) error {
+	c := compareNumber(i, r.max)
+	if c > 0 || (r.exclusive && c == 0) {
+		want := "at most"
+		if r.exclusive {
+			want = "less than"
+		}
+		return &ValidationError{
+			Code:     CodeMaximum,
+			Message:  fmt.Sprintf("%v is not %v %v", i, want, r.max),
+			Expected: r.max,
+		}
+	}
+	return nil
+}
+
+//Params returns the maximum, keyed by maximum or exclusiveMaximum.
+func (r MaxRule) Params() map[string]interface{} {
+	if r.exclusive {
+		return map[string]interface{}{"exclusiveMaximum": r.max}
+	}
+	return map[string]interface{}{"maximum": r.max}
+}
+
+func (r MaxRule) rulevalidation(p Props) error {
+	return numericRuleValidation("max", p)
+}
+
+//MultipleOfRule checks that a number is a multiple of a factor. it can be used with any
+// numeric property.
+type MultipleOfRule struct {
+	factor float64
+}
+
+//NewMultipleOfRule creates a MultipleOfRule. it returns a blank rule and an error if the
+// factor is not greater than zero.
+func NewMultipleOfRule(factor float64) (MultipleOfRule, error) {
+	if factor <= 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return MultipleOfRule{}, fmt.Errorf("multiple of factor must be greater than zero. got %v", factor)
+	}
+	return MultipleOfRule{factor: factor}, nil
+}
+
+//validate divides exactly. floats and the factor are read as the shortest decimal that
+// represents them, so decimal factors such as 0.1 work as expected.
+func (r MultipleOfRule) validate(i interface{}) error {
+	q := new(big.Rat).Quo(toRat(i), toRat(r.factor))
+	if !q.IsInt() {
+		return &ValidationError{
+			Code:     CodeMultipleOf,
+			Message:  fmt.Sprintf("%v is not a multiple of %v", i, r.factor),
+			Expected: r.factor,
+		}
+	}
+	return nil
+}
+
+//Params returns the factor of the rule.
+func (r MultipleOfRule) Params() map[string]interface{} {
+	return map[string]interface{}{"multipleOf": r.factor}
+}
+
+func (r MultipleOfRule) rulevalidation(p Props) error {
+	return numericRuleValidation("multiple of", p)
+}
+
+//MinLengthRule checks that a string has at least a minimum number of characters.
+// length is counted in runes, not bytes.
+type MinLengthRule struct {
+	min int
+}
+
+//NewMinLengthRule creates a MinLengthRule that requires at least min characters.
+func NewMinLengthRule(min int) MinLengthRule {
+	return MinLengthRule{min: min}
+}
+
+func (r MinLengthRule) validate(i interface{}) error {
+	if n := utf8.RuneCountInString(i.(string)); n < r.min {
+		return &ValidationError{
+			Code:     CodeMinLength,
+			Message:  fmt.Sprintf("has %v characters, want at least %v", n, r.min),
+			Expected: r.min,
+		}
+	}
+	return nil
+}
+
+//Params returns the minimum length.
+func (r MinLengthRule) Params() map[string]interface{} {
+	return map[string]interface{}{"minLength": r.min}
+}
+
+func (r MinLengthRule) rulevalidation(p Props) error {
+	return stringRuleValidation("min length", p)
+}
+
+//MaxLengthRule checks that a string has at most a maximum number of characters.
+// length is counted in runes, not bytes.
+type MaxLengthRule struct {
+	max int
+}
+
+//NewMaxLengthRule creates a MaxLengthRule that allows at most max characters.
+func NewMaxLengthRule(max int) MaxLengthRule {
+	return MaxLengthRule{max: max}
+}
+
+func (r MaxLengthRule) validate(i interface{}) error {
+	if n := utf8.RuneCountInString(i.(string)); n > r.max {
+		return &ValidationError{
+			Code:     CodeMaxLength,
+			Message:  fmt.Sprintf("has %v characters, want at most %v", n, r.max),
+			Expected: r.max,
+		}
+	}
+	return nil
+}
+
+//Params returns the maximum length.
+func (r MaxLengthRule) Params() map[string]interface{} {
+	return map[string]interface{}{"maxLength": r.max}
+}
+
+func (r MaxLengthRule) rulevalidation(p Props) error {
+	return stringRuleValidation("max length", p)
+}
+
+//numericRuleValidation checks that a rule meant for numbers is being added to a numeric property.
+func numericRuleValidation(name string, p Props) error {
+	if isNumeric(p.getType()) {
+		return nil
+	}
+	return fmt.Errorf("%v rule cannot be used with property. got type %v, need a number", name, p.getType().String())
+}
+
+//stringRuleValidation checks that a rule meant for strings is being added to a string property.
+func stringRuleValidation(name string, p Props) error {
+	if p.getType() != String {
+		return fmt.Errorf("%v rule cannot be used with property. got type %v, need string", name, p.getType().String())
+	}
+	return nil
+}
+
+//toFloat converts any go number to a float64. it reports false if the value is not a number.
+func toFloat(i interface{}) (float64, bool) {
+	val := reflect.ValueOf(i)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+	return 0, false
+}
+
+//compareNumber compares a number with a bound and returns -1, 0 or 1. integers are compared
+// with integer arithmetic when the bound is integral, and exactly otherwise, so values above
+// 2^53 keep their precision.
+func compareNumber(i interface{}, bound float64) int {
+	val := reflect.ValueOf(i)
+	integral := math.Trunc(bound) == bound
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integral && bound >= -9223372036854775808.0 && bound < 9223372036854775808.0 {
+			return compareInt64(val.Int(), int64(bound))
+		}
+		if !math.IsNaN(bound) {
+			return new(big.Float).SetInt64(val.Int()).Cmp(big.NewFloat(bound))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if integral && bound >= 0 && bound < 18446744073709551616.0 {
+			return compareUint64(val.Uint(), uint64(bound))
+		}
+		if !math.IsNaN(bound) {
+			return new(big.Float).SetUint64(val.Uint()).Cmp(big.NewFloat(bound))
+		}
+	}
+
+	f, _ := toFloat(i)
+	switch {
+	case f < bound:
+		return -1
+	case f > bound:
+		return 1
+	}
+	return 0
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+//toRat converts any go number to an exact rational. floats are read as the shortest decimal
+// that represents them.
+func toRat(i interface{}) *big.Rat {
+	val := reflect.ValueOf(i)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetUint64(val.Uint())
+	}
+	f, _ := toFloat(i)
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return rat
+}
+
+//numberKey formats a number so equal values have the same string. integral values are
+// written without a fraction. it reports false if the value is not a number.
+func numberKey(i interface{}) (string, bool) {
+	if n, ok := i.(json.Number); ok {
+		if v, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return strconv.FormatInt(v, 10), true
+		}
+		if v, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return strconv.FormatUint(v, 10), true
+		}
+		f, err := strconv.ParseFloat(string(n), 64)
+		if err != nil {
+			return "", false
+		}
+		i = f
+	}
+
+	val := reflect.ValueOf(i)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+		if math.Trunc(f) == f && f >= -9223372036854775808.0 && f < 9223372036854775808.0 {
+			return strconv.FormatInt(int64(f), 10), true
+		}
+		if math.Trunc(f) == f && f >= 0 && f < 18446744073709551616.0 {
+			return strconv.FormatUint(uint64(f), 10), true
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	}
+	return "", false
+}
@@ -1,7 +1,9 @@
 package validapi
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -51,6 +53,102 @@ func TestRegexRule(t *testing.T) {
 
 }
 
+func TestRegexRuleOptions(t *testing.T) {
+	partial, _ := NewRegexRule("[A-Z]+")
+	flagged, err := NewRegexRuleWithFlags("^[a-z]+$", "i")
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+	slug, err := NewRegexPreset("slug")
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+
+	testData := []struct {
+		name  string
+		rule  RegexRule
+		value string
+		pass  bool
+	}{
+		{"partial match", partial, "abC", true},
+		{"full match", partial.FullMatch(), "abC", false},
+		{"full match pass", partial.FullMatch(), "ABC", true},
+		{"alternation is anchored", mustRegex(t, "a|b").FullMatch(), "ab", false},
+		{"flags", flagged, "ABC", true},
+		{"flags full match", flagged.FullMatch(), "AbC", true},
+		{"multi-line full match", mustRegexFlags(t, "[a-z]+", "m").FullMatch(), "abc\n!!!", false},
+		{"multi-line full match pass", mustRegexFlags(t, "[a-z]+\n[a-z]+", "m").FullMatch(), "abc\ndef", true},
+		{"preset", slug, "my-post-1", true},
+		{"preset fail", slug, "My Post", false},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := i.rule.validate(i.value)
+			if i.pass && err != nil {
+				t.Errorf("wanted nil got %v", err.Error())
+			}
+			if !i.pass && err == nil {
+				t.Error("wanted error got nil")
+			}
+		})
+	}
+
+	t.Run("unknown flag", func(t *testing.T) {
+		if _, err := NewRegexRuleWithFlags("a", "x"); err == nil {
+			t.Error("wanted error got nil")
+		}
+	})
+
+	t.Run("blank rule", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("wanted panic got nil")
+			}
+		}()
+		blank, _ := NewRegexRule(")")
+		_ = NewProperty("test", String).AddRules(blank)
+	})
+
+	t.Run("unknown preset", func(t *testing.T) {
+		if _, err := NewRegexPreset("missing"); err == nil {
+			t.Error("wanted error got nil")
+		}
+	})
+
+	t.Run("message", func(t *testing.T) {
+		rule := partial.FullMatch()
+		rule.SetMessage("must be upper case")
+		err := rule.validate("abc").(*ValidationError)
+		if err.Message != "must be upper case" || err.Expected != nil {
+			t.Errorf("got message %q expected %v", err.Message, err.Expected)
+		}
+	})
+
+	t.Run("preset message", func(t *testing.T) {
+		err := slug.validate("A B").(*ValidationError)
+		if strings.Contains(err.Message, "^") || err.Expected != "slug" {
+			t.Errorf("got message %q expected %v", err.Message, err.Expected)
+		}
+	})
+}
+
+func mustRegexFlags(t *testing.T, str, flags string) RegexRule {
+	rule, err := NewRegexRuleWithFlags(str, flags)
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+	return rule
+}
+
+func mustRegex(t *testing.T, str string) RegexRule {
+	rule, err := NewRegexRule(str)
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+	return rule
+}
+
 func TestEnumRule(t *testing.T) {
 
 	t.Run("Build String Enum", func(t *testing.T) {
@@ -147,3 +245,190 @@ func TestCustomRule(t *testing.T) {
 		}
 	})
 }
+
+func TestArrayRules(t *testing.T) {
+	t.Run("Should fail to add to prop", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("wanted an error, got nil")
+			}
+		}()
+		_ = NewProperty("test", String).AddRules(NewMinItemsRule(1))
+	})
+
+	testData := []struct {
+		name  string
+		rule  Rule
+		value []interface{}
+		code  string
+	}{
+		{"min items", NewMinItemsRule(2), []interface{}{"a"}, CodeMinItems},
+		{"max items", NewMaxItemsRule(1), []interface{}{"a", "b"}, CodeMaxItems},
+		{"unique", NewUniqueItemsRule(), []interface{}{1.0, 2.0, 1.0}, CodeUniqueItems},
+		{"unique numbers by value", NewUniqueItemsRule(), []interface{}{1, 1.0}, CodeUniqueItems},
+		{"unique json numbers", NewUniqueItemsRule(), []interface{}{json.Number("1"), json.Number("1.0")}, CodeUniqueItems},
+		{"unique objects", NewUniqueItemsRule(), []interface{}{map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 1.0}}, CodeUniqueItems},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := i.rule.validate(i.value)
+			ve, ok := err.(*ValidationError)
+			if !ok || ve.Code != i.code {
+				t.Errorf("wanted %v error got %v", i.code, err)
+			}
+		})
+	}
+
+	if err := NewUniqueItemsRule().validate([]interface{}{"1", 1.0, map[string]interface{}{}, "b"}); err != nil {
+		t.Errorf("wanted nil got %v", err.Error())
+	}
+}
+
+func TestNumericRules(t *testing.T) {
+	multiple, err := NewMultipleOfRule(0.1)
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+	even, _ := NewMultipleOfRule(2)
+	tens, _ := NewMultipleOfRule(10)
+
+	testData := []struct {
+		name  string
+		rule  Rule
+		value interface{}
+		pass  bool
+	}{
+		{"min pass", NewMinRule(1), 1, true},
+		{"min fail", NewMinRule(1), 0.5, false},
+		{"exclusive min fail", NewExclusiveMinRule(1), 1.0, false},
+		{"exclusive min pass", NewExclusiveMinRule(1), 1.5, true},
+		{"max pass", NewMaxRule(10), 10, true},
+		{"max fail", NewMaxRule(10), 10.5, false},
+		{"exclusive max fail", NewExclusiveMaxRule(10), 10, false},
+		{"max int64 above 2^53", NewMaxRule(9007199254740992), int64(9007199254740993), false},
+		{"exclusive min int64 above 2^53", NewExclusiveMinRule(9007199254740992), int64(9007199254740993), true},
+		{"max uint near 2^64", NewExclusiveMaxRule(18446744073709551615), uint(18446744073709551615), true},
+		{"min negative int64", NewMinRule(-9007199254740992), int64(-9007199254740993), false},
+		{"multiple pass", multiple, 0.3, true},
+		{"multiple fail", multiple, 0.35, false},
+		{"multiple large pass", even, int64(1000000000), true},
+		{"multiple large odd", even, int64(1000000001), false},
+		{"multiple large odd float", even, 3000000001.0, false},
+		{"multiple large uint", tens, uint(12345678901), false},
+		{"multiple above 2^53", even, int64(9007199254740993), false},
+		{"min length pass", NewMinLengthRule(3), "héé", true},
+		{"min length fail", NewMinLengthRule(3), "hé", false},
+		{"max length pass", NewMaxLengthRule(3), "ééé", true},
+		{"max length fail", NewMaxLengthRule(3), "abcd", false},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := i.rule.validate(i.value)
+			if i.pass && err != nil {
+				t.Errorf("wanted nil got %v", err.Error())
+			}
+			if !i.pass && err == nil {
+				t.Error("wanted error got nil")
+			}
+		})
+	}
+
+	t.Run("invalid factor", func(t *testing.T) {
+		if _, err := NewMultipleOfRule(0); err == nil {
+			t.Error("wanted error got nil")
+		}
+	})
+
+	t.Run("Should fail to add to prop", func(t *testing.T) {
+		for _, rule := range []Rule{NewMinRule(1), NewMinLengthRule(1)} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("%T: wanted an error, got nil", rule)
+					}
+				}()
+				_ = NewProperty("test", Boolean).AddRules(rule)
+			}()
+		}
+	})
+
+	t.Run("Params", func(t *testing.T) {
+		var rule DescribedRule = NewExclusiveMaxRule(5)
+		if got := rule.Params()["exclusiveMaximum"]; got != 5.0 {
+			t.Errorf("wanted exclusiveMaximum 5 got %v", got)
+		}
+	})
+}
+
+func TestEnumRuleNumbers(t *testing.T) {
+	intEnum, err := NewEnumRule([]interface{}{1, 2, 3}, Int)
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+	floatEnum, err := NewEnumRule([]interface{}{1, 2.5}, Float)
+	if err != nil {
+		t.Fatalf("could not build rule for testing. err: %v", err.Error())
+	}
+
+	testData := []struct {
+		name  string
+		rule  EnumRule
+		value interface{}
+		pass  bool
+	}{
+		{"int", intEnum, 2, true},
+		{"float64", intEnum, 2.0, true},
+		{"json number", intEnum, json.Number("3"), true},
+		{"json number exponent", intEnum, json.Number("1e0"), true},
+		{"float64 fail", intEnum, 2.5, false},
+		{"string is not a number", intEnum, "1", false},
+		{"float enum", floatEnum, json.Number("2.5"), true},
+		{"float enum int member", floatEnum, 1.0, true},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := i.rule.validate(i.value)
+			if i.pass && err != nil {
+				t.Errorf("wanted nil got %v", err.Error())
+			}
+			if !i.pass && err == nil {
+				t.Error("wanted error got nil")
+			}
+		})
+	}
+
+	t.Run("non integral member", func(t *testing.T) {
+		if _, err := NewEnumRule([]interface{}{1.5}, Int); err == nil {
+			t.Error("wanted error got nil")
+		}
+	})
+
+	t.Run("body value", func(t *testing.T) {
+		prop := NewProperty("status", Int).AddRules(intEnum)
+		if err := prop.validate("/status", 3.0); err != nil {
+			t.Errorf("wanted nil got %v", err.Error())
+		}
+	})
+}
+
+func TestEnumRuleStrings(t *testing.T) {
+	enum, _ := NewEnumRule([]interface{}{"Red", "Green"}, String)
+
+	err := enum.validate("red")
+	if err == nil {
+		t.Fatal("wanted error got nil")
+	}
+	if !strings.Contains(err.Error(), "Red, Green") {
+		t.Errorf("error should list allowed values. got %v", err.Error())
+	}
+
+	if err := enum.IgnoreCase().validate("GREEN"); err != nil {
+		t.Errorf("wanted nil got %v", err.Error())
+	}
+	if err := enum.validate("GREEN"); err == nil {
+		t.Error("IgnoreCase should not change the original rule")
+	}
+}
@@ -0,0 +1,60 @@
+package validapi
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+//ServeFiles serves files from the provided http.FileSystem on GET requests matching the
+// pattern, which must end with a catch-all segment such as "/assets/*filepath". the
+// captured path is used as the name of the file. an embed.FS can be served by wrapping
+// it with http.FS.
+func (r *Router) ServeFiles(pattern string, fs http.FileSystem) *Endpoint {
+	return r.Get(pattern, fileHandler(fs, catchAllName(pattern), false))
+}
+
+//ServeSPA works like ServeFiles, but requests for files that do not exist are answered
+// with the index.html at the root of the file system so a single page application can
+// handle its own routing.
+func (r *Router) ServeSPA(pattern string, fs http.FileSystem) *Endpoint {
+	return r.Get(pattern, fileHandler(fs, catchAllName(pattern), true))
+}
+
+//catchAllName returns the name of the catch-all segment that ends the pattern.
+// will panic if the pattern does not end with one.
+func catchAllName(pattern string) string {
+	segs := splitPath(pattern)
+	if len(segs) == 0 || !strings.HasPrefix(segs[len(segs)-1], "*") {
+		panic(fmt.Errorf("path %v must end with a catch-all segment to serve files", pattern))
+	}
+	return segs[len(segs)-1][1:]
+}
+
+func fileHandler(fs http.FileSystem, param string, spa bool) http.HandlerFunc {
+	server := http.FileServer(fs)
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := path.Clean("/" + Param(r, param))
+		if spa {
+			f, err := fs.Open(name)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					ProblemRenderer(w, r, http.StatusInternalServerError, err)
+					return
+				}
+				name = "/"
+			} else {
+				f.Close()
+			}
+		}
+
+		u := *r.URL
+		u.Path = name
+		u.RawPath = ""
+		req := r.WithContext(r.Context())
+		req.URL = &u
+		server.ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,76 @@
+package validapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testFileSystem(t *testing.T) http.FileSystem {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html": "index",
+		"app.js":     "app",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return http.Dir(dir)
+}
+
+func TestCatchAllParam(t *testing.T) {
+	api := New()
+	var got string
+	api.Get("/files/*path", func(w http.ResponseWriter, r *http.Request) {
+		got = Param(r, "path")
+	})
+
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/files/css/main.css", nil))
+	if got != "css/main.css" {
+		t.Errorf("got %v want css/main.css", got)
+	}
+}
+
+func TestServeFiles(t *testing.T) {
+	api := New()
+	fs := testFileSystem(t)
+	api.ServeFiles("/assets/*filepath", fs)
+	api.ServeSPA("/app/*filepath", fs)
+
+	testData := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/assets/app.js", http.StatusOK, "app"},
+		{"/assets/missing.js", http.StatusNotFound, ""},
+		{"/app/app.js", http.StatusOK, "app"},
+		{"/app/users/12", http.StatusOK, "index"},
+		{"/app", http.StatusOK, "index"},
+	}
+
+	for _, i := range testData {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, i.path, nil))
+		if rec.Code != i.status {
+			t.Errorf("%v: got status %v want %v", i.path, rec.Code, i.status)
+		}
+		if i.body != "" && !strings.Contains(rec.Body.String(), i.body) {
+			t.Errorf("%v: got body %v want %v", i.path, rec.Body.String(), i.body)
+		}
+	}
+}
+
+func TestServeFilesNeedsCatchAll(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("wanted panic got nil")
+		}
+	}()
+	New().ServeFiles("/assets", http.Dir("."))
+}
@@ -1,7 +1,10 @@
 package validapi
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 type (
@@ -9,49 +12,336 @@ type (
 	//node represents a single position on the tree. its created as an interface
 	// to allow both route and router to serve as stops.
 	node interface {
-		//find should accept a string array and locate the desired route handler,
-		// wrapped in an array for the purpose of creating a middleware chain.
-		find([]string) ([]*Middleware, *leaf)
-		//add should implement the logic for creating new nodes and children.
-		add([]string)
+		//find should accept a method and a string array and locate the desired route handler.
+		// middleware found along the way is appended to the provided slice for the purpose of
+		// creating a middleware chain, and the values captured along the branch that matched
+		// are recorded in the match. if nothing matches, the returned leaf is nil.
+		find(string, []string, []*Middleware, *match) ([]*Middleware, *leaf)
+		//add should implement the logic for creating new nodes and children. it returns
+		// the Route found at the end of the provided path, or an error if the path
+		// conflicts with the routes already below the node.
+		add([]string, string) (*Route, error)
+		//route should return the Route the node is built on.
+		route() *Route
+		//allowed should add the methods of every route matching the path to the provided set.
+		allowed([]string, map[string]struct{})
+		//walk should call the function for every leaf below the node. it receives the pattern
+		// of the parent node and the number of middleware collected above the node.
+		walk(string, int, func(RouteInfo) error) error
 	}
 
 	//leaf represents a combination of handler and propertygroup. It serves as the end of the tree.
-	leaf struct{}
+	leaf struct {
+		pattern    string
+		handler    http.HandlerFunc
+		group      *PropertyGroup
+		params     map[string]*Property
+		middleware []Middleware
+	}
 
-	//Route represents a position on the tree that can
+	//Route represents a position on the tree that can hold handlers and children.
 	Route struct {
 		Path           string
 		handlers       map[string]*leaf
-		staticChildren map[string]*node
-		variableChild  *leaf
-		defaultChild   *leaf
+		staticChildren map[string]node
+		variableChild  *Route
+		defaultChild   *Route
 	}
 
-	//Router an extension of Route that contains a list of middleware functions.
+	//Router an extension of Route that contains a list of middleware functions and
+	// the CORS policy used for the routes below it.
 	Router struct {
 		Route
-		middleware []func(http.HandlerFunc) http.HandlerFunc
+		CORS       *CORSPolicy
+		middleware []Middleware
+	}
+
+	//match the values captured while finding the leaf of a request: the path parameters and
+	// the CORSPolicy of the innermost Router with a policy on the branch that matched.
+	match struct {
+		params Params
+		policy *CORSPolicy
 	}
 
 	//Middleware wrapper type for the func(http.Handlerfunc) http.HandlerFunc
 	Middleware func(http.HandlerFunc) http.HandlerFunc
 )
 
-func (r *Route) add(uri []string) {
-	panic("not implemented")
+//splitPath breaks a url path or route pattern into its segments. leading and trailing
+// slashes are ignored, so "/", "" and "/users/" split to [] and ["users"].
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+//add creates the children needed to reach the end of the path. method is the method the
+// route is being added for, and is used to detect routes shadowed by a catch-all. it can be
+// left empty when the route is not added for a method. returns an error when the path
+// conflicts with a route that is already on the tree.
+func (r *Route) add(uri []string, method string) (*Route, error) {
+	if len(uri) == 0 {
+		return r, nil
+	}
+	seg := uri[0]
+	last := len(uri) == 1
+
+	//every check runs before a child is created, and children created for the path are
+	// removed again if the rest of the path fails, so a failed add leaves the tree unchanged.
+	switch {
+	case strings.HasPrefix(seg, ":"):
+		if r.variableChild != nil && r.variableChild.Path != seg {
+			return nil, fmt.Errorf("parameter %v conflicts with parameter %v of route %v", seg, r.variableChild.Path, r.variableChild.firstPattern())
+		}
+		if last {
+			if l, ok := r.defaultChild.leafFor(method); ok {
+				return nil, fmt.Errorf("shadowed by catch-all route %v", l.pattern)
+			}
+		}
+		created := r.variableChild == nil
+		if created {
+			r.variableChild = &Route{Path: seg}
+		}
+		rt, err := r.variableChild.add(uri[1:], method)
+		if err != nil && created {
+			r.variableChild = nil
+		}
+		return rt, err
+
+	case strings.HasPrefix(seg, "*"):
+		if !last {
+			return nil, fmt.Errorf("catch-all segment %v must be the last segment of a path", seg)
+		}
+		if r.defaultChild != nil && r.defaultChild.Path != seg {
+			return nil, fmt.Errorf("catch-all %v conflicts with catch-all %v of route %v", seg, r.defaultChild.Path, r.defaultChild.firstPattern())
+		}
+		for _, child := range r.staticChildren {
+			if l, ok := child.route().leafFor(method); ok {
+				return nil, fmt.Errorf("catch-all shadows route %v", l.pattern)
+			}
+		}
+		if l, ok := r.variableChild.leafFor(method); ok {
+			return nil, fmt.Errorf("catch-all shadows route %v", l.pattern)
+		}
+		if r.defaultChild == nil {
+			r.defaultChild = &Route{Path: seg}
+		}
+		return r.defaultChild, nil
+
+	default:
+		if last {
+			if l, ok := r.defaultChild.leafFor(method); ok {
+				return nil, fmt.Errorf("shadowed by catch-all route %v", l.pattern)
+			}
+		}
+		child, ok := r.staticChildren[seg]
+		if !ok {
+			child = &Route{Path: seg}
+		}
+		rt, err := child.add(uri[1:], method)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if r.staticChildren == nil {
+				r.staticChildren = make(map[string]node)
+			}
+			r.staticChildren[seg] = child
+		}
+		return rt, nil
+	}
+}
+
+//staticRoute follows the static children along the path and returns the Route at its end,
+// or nil if the path is not on the tree yet.
+func (r *Route) staticRoute(uri []string) *Route {
+	rt := r
+	for _, seg := range uri {
+		child, ok := rt.staticChildren[seg]
+		if !ok {
+			return nil
+		}
+		rt = child.route()
+	}
+	return rt
+}
+
+//leafFor returns the leaf registered for the method on the route. it is safe to call on a
+// nil route.
+func (r *Route) leafFor(method string) (*leaf, bool) {
+	if r == nil {
+		return nil, false
+	}
+	l, ok := r.handlers[method]
+	return l, ok
+}
+
+//firstPattern returns the pattern of a leaf at or below the route, for use in error messages.
+func (r *Route) firstPattern() string {
+	for _, l := range r.handlers {
+		return l.pattern
+	}
+	for _, child := range r.staticChildren {
+		if pattern := child.route().firstPattern(); pattern != "" {
+			return pattern
+		}
+	}
+	for _, child := range []*Route{r.variableChild, r.defaultChild} {
+		if child != nil {
+			if pattern := child.firstPattern(); pattern != "" {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
+func (r *Route) route() *Route {
+	return r
+}
+
+//find walks the tree one segment at a time. static children are preferred over the
+// variable child, which is preferred over the catch-all. if a branch fails to match
+// further down the tree, the next option at this position is tried instead. the catch-all
+// captures the rest of the path, without a leading slash, as its parameter.
+func (r *Route) find(method string, uri []string, mw []*Middleware, m *match) ([]*Middleware, *leaf) {
+	if len(uri) == 0 {
+		if l, ok := r.handlers[method]; ok {
+			return mw, l
+		}
+		if r.defaultChild != nil {
+			if l, ok := r.defaultChild.handlers[method]; ok {
+				m.params = append(m.params, PathParam{Key: r.defaultChild.Path[1:]})
+				return mw, l
+			}
+		}
+		return mw, nil
+	}
+
+	if child, ok := r.staticChildren[uri[0]]; ok {
+		if found, l := child.find(method, uri[1:], mw, m); l != nil {
+			return found, l
+		}
+	}
+
+	if r.variableChild != nil {
+		n := len(m.params)
+		m.params = append(m.params, PathParam{Key: r.variableChild.Path[1:], Value: uri[0]})
+		if found, l := r.variableChild.find(method, uri[1:], mw, m); l != nil {
+			return found, l
+		}
+		m.params = m.params[:n]
+	}
+
+	if r.defaultChild != nil {
+		if l, ok := r.defaultChild.handlers[method]; ok {
+			m.params = append(m.params, PathParam{Key: r.defaultChild.Path[1:], Value: strings.Join(uri, "/")})
+			return mw, l
+		}
+	}
+
+	return mw, nil
+}
+
+//allowed mirrors find, but instead of stopping at the first match it collects the methods
+// of every route that matches the path. A method is in the set if find would succeed for it.
+func (r *Route) allowed(uri []string, methods map[string]struct{}) {
+	if len(uri) == 0 {
+		for method := range r.handlers {
+			methods[method] = struct{}{}
+		}
+	} else {
+		if child, ok := r.staticChildren[uri[0]]; ok {
+			child.allowed(uri[1:], methods)
+		}
+		if r.variableChild != nil {
+			r.variableChild.allowed(uri[1:], methods)
+		}
+	}
+
+	if r.defaultChild != nil {
+		for method := range r.defaultChild.handlers {
+			methods[method] = struct{}{}
+		}
+	}
+}
+
+//walk visits the handlers of the route sorted by method, then the static children sorted
+// by path, the variable child and the catch-all.
+func (r *Route) walk(prefix string, mw int, fn func(RouteInfo) error) error {
+	return r.walkAt(strings.TrimRight(prefix, "/")+"/"+r.Path, mw, fn)
+}
+
+//walkAt works like walk, using pattern as the full pattern of the route.
+func (r *Route) walkAt(pattern string, mw int, fn func(RouteInfo) error) error {
+	methods := make([]string, 0, len(r.handlers))
+	for method := range r.handlers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		if err := fn(r.handlers[method].info(method, pattern, mw)); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(r.staticChildren))
+	for key := range r.staticChildren {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := r.staticChildren[key].walk(pattern, mw, fn); err != nil {
+			return err
+		}
+	}
+
+	if r.variableChild != nil {
+		if err := r.variableChild.walk(pattern, mw, fn); err != nil {
+			return err
+		}
+	}
+	if r.defaultChild != nil {
+		return r.defaultChild.walk(pattern, mw, fn)
+	}
+	return nil
 }
 
-func (r *Route) find(uri []string) ([]*Middleware, *leaf) {
-	panic("not implemented")
-	// return []*http.HandlerFunc{}, &leaf{}
+//handle sets the leaf for the provided method on the route.
+func (r *Route) handle(method string, l *leaf) {
+	if r.handlers == nil {
+		r.handlers = make(map[string]*leaf)
+	}
+	r.handlers[method] = l
+}
+
+func (r *Router) add(uri []string, method string) (*Route, error) {
+	return r.Route.add(uri, method)
+}
+
+func (r *Router) route() *Route {
+	return &r.Route
+}
+
+//find records the policy of the router when the leaf was found below it, unless a router
+// further down the branch already set one.
+func (r *Router) find(method string, uri []string, mw []*Middleware, m *match) ([]*Middleware, *leaf) {
+	for i := range r.middleware {
+		mw = append(mw, &r.middleware[i])
+	}
+	found, l := r.Route.find(method, uri, mw, m)
+	if l != nil && m.policy == nil {
+		m.policy = r.CORS
+	}
+	return found, l
 }
 
-func (r *Router) add(uri []string) {
-	panic("not implemented")
+func (r *Router) allowed(uri []string, methods map[string]struct{}) {
+	r.Route.allowed(uri, methods)
 }
 
-func (r *Router) find(uri []string) ([]*Middleware, *leaf) {
-	panic("not implemented")
-	// return []*http.HandlerFunc{}, &leaf{}
+func (r *Router) walk(prefix string, mw int, fn func(RouteInfo) error) error {
+	return r.Route.walk(prefix, mw+len(r.middleware), fn)
 }
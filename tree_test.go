@@ -0,0 +1,201 @@
+package validapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	testData := []struct {
+		path string
+		want int
+	}{
+		{"", 0},
+		{"/", 0},
+		{"/users", 1},
+		{"/users/", 1},
+		{"/users/:id/posts", 3},
+	}
+
+	for _, i := range testData {
+		if got := splitPath(i.path); len(got) != i.want {
+			t.Errorf("%v: got %v segments, want %v", i.path, len(got), i.want)
+		}
+	}
+}
+
+func TestRouteFind(t *testing.T) {
+	root := &Router{}
+	users := &leaf{}
+	user := &leaf{}
+	newUser := &leaf{}
+	edit := &leaf{}
+	assets := &leaf{}
+
+	mustAdd(t, root, http.MethodGet, "/users", users)
+	mustAdd(t, root, http.MethodGet, "/users/:id", user)
+	mustAdd(t, root, http.MethodGet, "/users/new", newUser)
+	mustAdd(t, root, http.MethodGet, "/users/:id/edit", edit)
+	mustAdd(t, root, http.MethodGet, "/assets/*filepath", assets)
+
+	testData := []struct {
+		path string
+		want *leaf
+	}{
+		{"/users", users},
+		{"/users/12", user},
+		{"/users/new", newUser},
+		{"/users/new/edit", edit},
+		{"/users/12/edit", edit},
+		{"/assets/css/main.css", assets},
+		{"/assets", assets},
+		{"/nope", nil},
+		{"/users/12/nope", nil},
+	}
+
+	for _, i := range testData {
+		_, got := root.find(http.MethodGet, splitPath(i.path), nil, &match{})
+		if got != i.want {
+			t.Errorf("%v: found the wrong leaf", i.path)
+		}
+	}
+
+	if _, got := root.find(http.MethodPost, splitPath("/users"), nil, &match{}); got != nil {
+		t.Error("wanted nil leaf for unregistered method")
+	}
+}
+
+func TestRouteFindParams(t *testing.T) {
+	root := &Router{}
+	mustAdd(t, root, http.MethodGet, "/users/new", &leaf{})
+	mustAdd(t, root, http.MethodGet, "/users/:id/edit", &leaf{})
+
+	m := match{}
+	if _, l := root.find(http.MethodGet, splitPath("/users/new/edit"), nil, &m); l == nil {
+		t.Fatal("wanted leaf got nil")
+	}
+	if len(m.params) != 1 || m.params.Get("id") != "new" {
+		t.Errorf("wanted id=new got %v", m.params)
+	}
+
+	m = match{}
+	if _, l := root.find(http.MethodGet, splitPath("/users/12/nope"), nil, &m); l != nil {
+		t.Fatal("wanted nil leaf")
+	}
+	if len(m.params) != 0 {
+		t.Errorf("params should be discarded when a branch fails. got %v", m.params)
+	}
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	mw := func(next http.HandlerFunc) http.HandlerFunc { return next }
+	root := &Router{middleware: []Middleware{mw, mw}}
+	mustAdd(t, root, http.MethodGet, "/users", &leaf{})
+
+	found, l := root.find(http.MethodGet, splitPath("/users"), nil, &match{})
+	if l == nil {
+		t.Fatal("wanted leaf got nil")
+	}
+	if len(found) != 2 {
+		t.Errorf("wanted 2 middleware got %v", len(found))
+	}
+}
+
+func TestCatchAllMustBeLast(t *testing.T) {
+	root := &Router{}
+	if _, err := root.add(splitPath("/assets/*filepath/more"), http.MethodGet); err == nil {
+		t.Error("wanted error got nil")
+	}
+}
+
+func TestAddConflicts(t *testing.T) {
+	testData := []struct {
+		name     string
+		existing string
+		pattern  string
+	}{
+		{"duplicate", "/users/:id", "/users/:id"},
+		{"parameter names", "/users/:id", "/users/:name/posts"},
+		{"catch-all names", "/files/*path", "/files/*name"},
+		{"catch-all shadows static", "/files/readme", "/files/*path"},
+		{"static shadowed by catch-all", "/files/*path", "/files/readme"},
+		{"catch-all shadows parameter", "/files/:name", "/files/*path"},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			api := New()
+			api.Get(i.existing, nil)
+			_, err := api.AddRoute(http.MethodGet, i.pattern, nil)
+			if err == nil {
+				t.Fatal("wanted error got nil")
+			}
+			if !strings.Contains(err.Error(), i.existing) || !strings.Contains(err.Error(), i.pattern) {
+				t.Errorf("error should name both patterns. got %v", err.Error())
+			}
+		})
+	}
+
+	t.Run("different methods", func(t *testing.T) {
+		api := New()
+		api.Get("/files/readme", nil)
+		if _, err := api.AddRoute(http.MethodPost, "/files/*path", nil); err != nil {
+			t.Errorf("wanted nil got %v", err.Error())
+		}
+	})
+
+	t.Run("handle panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("wanted panic got nil")
+			}
+		}()
+		api := New()
+		api.Get("/users", nil)
+		api.Get("/users", nil)
+	})
+}
+
+func TestFailedAddLeavesTree(t *testing.T) {
+	testData := []struct {
+		name     string
+		existing string
+		failed   string
+		pattern  string
+	}{
+		{"parameter", "/a/*all", "/a/:x", "/a/:y/z"},
+		{"static", "/a/*all", "/b/c/:x/*rest/d", "/b/c/:y"},
+		{"nested parameter", "/a/:x/*all", "/a/:x/:y", "/a/:x/:z/b"},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			api := New()
+			api.Get(i.existing, nil)
+			if _, err := api.AddRoute(http.MethodGet, i.failed, nil); err == nil {
+				t.Fatal("wanted error got nil")
+			}
+			if _, err := api.AddRoute(http.MethodGet, i.pattern, nil); err != nil {
+				t.Errorf("wanted nil got %v", err.Error())
+			}
+		})
+	}
+
+	t.Run("mount", func(t *testing.T) {
+		api := New()
+		if _, err := api.AddRoute(http.MethodGet, "/admin/*all/users", nil); err == nil {
+			t.Fatal("wanted error got nil")
+		}
+		api.Mount("/admin", NewRouter())
+	})
+}
+
+func mustAdd(t *testing.T, root *Router, method, pattern string, l *leaf) {
+	t.Helper()
+	rt, err := root.add(splitPath(pattern), method)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.handle(method, l)
+}
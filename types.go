@@ -1,7 +1,11 @@
 package validapi
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 )
 
 //Type used as a wrapper for reflect.Types in case I wish to change
@@ -12,14 +16,178 @@ type Type reflect.Type
 //String string type variable.
 var String Type = reflect.TypeOf("")
 
-//Int int type variable.
+//Int int type variable. values must be integral numbers that fit in a go int.
 var Int Type = reflect.TypeOf(1)
 
+//Int8 int8 type variable. values must be integral numbers that fit in an int8.
+var Int8 Type = reflect.TypeOf(int8(1))
+
+//Int16 int16 type variable. values must be integral numbers that fit in an int16.
+var Int16 Type = reflect.TypeOf(int16(1))
+
+//Int32 int32 type variable. values must be integral numbers that fit in an int32.
+var Int32 Type = reflect.TypeOf(int32(1))
+
+//Int64 int64 type variable. values must be integral numbers that fit in an int64.
+var Int64 Type = reflect.TypeOf(int64(1))
+
+//Uint uint type variable. values must be non negative integral numbers that fit in a go uint.
+var Uint Type = reflect.TypeOf(uint(1))
+
+//Uint8 uint8 type variable. values must be non negative integral numbers that fit in a uint8.
+var Uint8 Type = reflect.TypeOf(uint8(1))
+
+//Uint16 uint16 type variable. values must be non negative integral numbers that fit in a uint16.
+var Uint16 Type = reflect.TypeOf(uint16(1))
+
+//Uint32 uint32 type variable. values must be non negative integral numbers that fit in a uint32.
+var Uint32 Type = reflect.TypeOf(uint32(1))
+
+//Uint64 uint64 type variable. values must be non negative integral numbers that fit in a uint64.
+var Uint64 Type = reflect.TypeOf(uint64(1))
+
 //Float float type variable.
 var Float Type = reflect.TypeOf(1.12)
 
 //Boolean boolean type variable.
 var Boolean Type = reflect.TypeOf(true)
 
+//Array array type variable. used by ArrayProperty.
+var Array Type = reflect.TypeOf([]interface{}{})
+
 //Group PropertyGroup type variable.
 var Group = reflect.TypeOf(PropertyGroup{})
+
+//isNumeric reports if the Type is one of the integer or float types.
+func isNumeric(t Type) bool {
+	return isInteger(t) || t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
+
+//isInteger reports if the Type is a signed or unsigned integer type.
+func isInteger(t Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+//convertNumber converts a number to the go type of the numeric Type t, so rules always
+// receive the same type for a property. numbers can be any go number or a json.Number,
+// which is parsed directly so large integers keep their precision. integer Types only
+// accept integral values that fit in the type.
+func convertNumber(path string, value interface{}, t Type) (interface{}, *ValidationError) {
+	if n, ok := value.(json.Number); ok {
+		return convertJSONNumber(path, n, t)
+	}
+
+	val := reflect.ValueOf(value)
+	out := reflect.New(t).Elem()
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return setInt(path, value, out, val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return setUint(path, value, out, val.Uint())
+	case reflect.Float32, reflect.Float64:
+		return setFloat(path, value, out, val.Float())
+	}
+	return nil, typeMismatch(path, val.Type().String(), t.String())
+}
+
+//convertJSONNumber parses the number as an integer first, falling back to a float for
+// numbers written with a fraction or exponent, such as 1.0 or 1e3.
+func convertJSONNumber(path string, n json.Number, t Type) (interface{}, *ValidationError) {
+	out := reflect.New(t).Elem()
+	switch out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return setInt(path, n, out, i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return setUint(path, n, out, i)
+		}
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return nil, outOfRange(path, n, t)
+	}
+	return setFloat(path, n, out, f)
+}
+
+func setInt(path string, value interface{}, out reflect.Value, i int64) (interface{}, *ValidationError) {
+	switch out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if out.OverflowInt(i) {
+			return nil, outOfRange(path, value, out.Type())
+		}
+		out.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i < 0 || out.OverflowUint(uint64(i)) {
+			return nil, outOfRange(path, value, out.Type())
+		}
+		out.SetUint(uint64(i))
+	default:
+		out.SetFloat(float64(i))
+	}
+	return out.Interface(), nil
+}
+
+func setUint(path string, value interface{}, out reflect.Value, i uint64) (interface{}, *ValidationError) {
+	switch out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i > math.MaxInt64 {
+			return nil, outOfRange(path, value, out.Type())
+		}
+		return setInt(path, value, out, int64(i))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if out.OverflowUint(i) {
+			return nil, outOfRange(path, value, out.Type())
+		}
+		out.SetUint(i)
+	default:
+		out.SetFloat(float64(i))
+	}
+	return out.Interface(), nil
+}
+
+func setFloat(path string, value interface{}, out reflect.Value, f float64) (interface{}, *ValidationError) {
+	if out.Kind() == reflect.Float32 || out.Kind() == reflect.Float64 {
+		if out.OverflowFloat(f) {
+			return nil, outOfRange(path, value, out.Type())
+		}
+		out.SetFloat(f)
+		return out.Interface(), nil
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) || math.Trunc(f) != f {
+		return nil, &ValidationError{
+			Path:     path,
+			Code:     CodeTypeMismatch,
+			Message:  fmt.Sprintf("invalid type. %v is not an integer, want %v", value, out.Type().String()),
+			Expected: out.Type().String(),
+			Actual:   value,
+		}
+	}
+	//2^63 and 2^64 are exactly representable as float64, any integral float below them
+	// converts without loss.
+	if f >= -9223372036854775808.0 && f < 9223372036854775808.0 {
+		return setInt(path, value, out, int64(f))
+	}
+	if f >= 0 && f < 18446744073709551616.0 {
+		return setUint(path, value, out, uint64(f))
+	}
+	return nil, outOfRange(path, value, out.Type())
+}
+
+//outOfRange builds the error for a number that does not fit in the Type.
+func outOfRange(path string, value interface{}, t Type) *ValidationError {
+	return &ValidationError{
+		Path:     path,
+		Code:     CodeOutOfRange,
+		Message:  fmt.Sprintf("%v does not fit in %v", value, t.String()),
+		Expected: t.String(),
+		Actual:   value,
+	}
+}
@@ -1,41 +1,438 @@
 package validapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 //exposed funcs to make working with this package easier.
 
 //PropsFromType receives a reflect.Type of a struct and
-// returns a propertygroup based of the field name and types of the struct
-// useful for creating propertygroups that dont need any specific rules applied to them.
-// will panic if the provided type's kind is not a struct.
+// returns a propertygroup based of the field name and types of the struct.
+// properties are named after the json tag of the field, and fields tagged json:"-" or
+// unexported fields are skipped. the validapi tag marks properties as required and adds
+// rules, for example validapi:"required,min=3,max=50,format=email,enum=a|b".
+// min and max limit the value of numbers, the length of strings and the number of items
+// in slices, including slices of structs. format and enum are applied to the items of
+// slices of basic types.
+//
+// nested structs become ObjectProperties, slices become ArrayProperties, or ObjectProperties
+// for slices of structs, and map[string]T becomes a MapProperty. pointers are nullable,
+// time.Time is a date-time string and []byte is a base64 string. the fields of embedded
+// structs are flattened the way encoding/json does it, and recursive types share the group
+// of the type. will panic if the provided type's kind is not a struct, a field's type is
+// not supported, or a tag is invalid.
 func PropsFromType(t reflect.Type) *PropertyGroup {
 	if t.Kind() != reflect.Struct {
 		panic(fmt.Errorf("myapi.PropsFromType received kind %v. wanted struct", t.Kind()))
 	}
+	b := typeBuilder{groups: make(map[reflect.Type]*PropertyGroup)}
+	return b.group(t)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//typeBuilder builds properties from go types. groups are cached by type, so a recursive type
+// uses the group that is being built instead of building it again forever.
+type typeBuilder struct {
+	groups map[reflect.Type]*PropertyGroup
+}
 
+//group returns the PropertyGroup for the fields of the struct type t.
+func (b *typeBuilder) group(t reflect.Type) *PropertyGroup {
+	if pg, ok := b.groups[t]; ok {
+		return pg
+	}
 	propGroup := NewPropertyGroup()
-	for i := 0; i < t.NumField(); i++ {
-		var prop *Property
-		field := t.Field(i)
-		name := field.Name
-		switch field.Type {
-		case Int:
-			prop = NewProperty(name, Int)
-		case String:
-			prop = NewProperty(name, String)
-		case Float:
-			prop = NewProperty(name, Float)
-		case Boolean:
-			prop = NewProperty(name, Boolean)
+	b.groups[t] = propGroup
+	for _, f := range structFields(t) {
+		prop := b.prop(f.name, f.field.Type)
+		if err := applyTag(prop, f.field.Tag.Get("validapi")); err != nil {
+			panic(fmt.Errorf("invalid validapi tag on field %v. error: %v", f.field.Name, err))
+		}
+		propGroup.AddProperties(prop)
+	}
+	return propGroup
+}
+
+//prop returns the property for a value of type t.
+func (b *typeBuilder) prop(name string, t reflect.Type) Props {
+	switch {
+	case t.Kind() == reflect.Ptr:
+		return nullable(b.prop(name, t.Elem()))
+	case t == timeType:
+		return NewProperty(name, String).AddRules(mustFormatRule("date-time"))
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		//encoding/json encodes byte slices as base64 strings.
+		return NewProperty(name, String).AddRules(mustFormatRule("base64"))
+	case t.Kind() == reflect.Struct:
+		return NewObjectProperty(name, false).UsePropertyGroup(b.group(t))
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		switch item := b.prop(name, t.Elem()).(type) {
+		case *Property:
+			array := NewArrayProperty(name, item.propType)
+			array.item = item
+			return array
+		case *ObjectProperty:
+			if !item.slice {
+				return NewObjectProperty(name, true).UsePropertyGroup(item.group)
+			}
+		}
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		return NewMapProperty(name, b.prop(name, t.Elem()))
+	default:
+		if typ, ok := basicType(t); ok {
+			return NewProperty(name, typ)
+		}
+	}
+	panic(fmt.Errorf("type of %v not supported", t.String()))
+}
+
+//basicType returns the Type used for values of go type t. types are matched by kind, so
+// named types such as type Role string are supported.
+func basicType(t reflect.Type) (Type, bool) {
+	switch t.Kind() {
+	case reflect.Int:
+		return Int, true
+	case reflect.Int8:
+		return Int8, true
+	case reflect.Int16:
+		return Int16, true
+	case reflect.Int32:
+		return Int32, true
+	case reflect.Int64:
+		return Int64, true
+	case reflect.Uint:
+		return Uint, true
+	case reflect.Uint8:
+		return Uint8, true
+	case reflect.Uint16:
+		return Uint16, true
+	case reflect.Uint32:
+		return Uint32, true
+	case reflect.Uint64:
+		return Uint64, true
+	case reflect.String:
+		return String, true
+	case reflect.Float32, reflect.Float64:
+		return Float, true
+	case reflect.Bool:
+		return Boolean, true
+	}
+	return nil, false
+}
+
+//nullable allows the value of the property to be null.
+func nullable(prop Props) Props {
+	switch p := prop.(type) {
+	case *Property:
+		p.Nullable()
+	case *ObjectProperty:
+		p.Nullable()
+	case *ArrayProperty:
+		p.Nullable()
+	case *MapProperty:
+		p.Nullable()
+	}
+	return prop
+}
+
+//mustFormatRule returns the rule of a built in format.
+func mustFormatRule(name string) FormatRule {
+	rule, err := NewFormatRule(name)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+//structField a field that becomes a property of a struct's group. depth is the number of
+// embedded structs the field was promoted through.
+type structField struct {
+	name   string
+	tagged bool
+	depth  int
+	field  reflect.StructField
+}
+
+//structFields returns the fields of the struct type t that become properties, following the
+// rules of encoding/json. the fields of embedded structs without a json name are promoted, and
+// when several fields have the same name, the shallowest one is used. fields of the same depth
+// are dropped, unless exactly one of them is named by a json tag.
+func structFields(t reflect.Type) []structField {
+	fields := []structField{}
+	visited := make(map[reflect.Type]bool)
+	current := []reflect.Type{t}
+	for depth := 0; len(current) > 0; depth++ {
+		next := []reflect.Type{}
+		for _, typ := range current {
+			//a struct embedded more than once, directly or through a pointer to itself, is only
+			// expanded at the shallowest depth.
+			if visited[typ] {
+				continue
+			}
+			visited[typ] = true
+
+			for i := 0; i < typ.NumField(); i++ {
+				field := typ.Field(i)
+				name, tagged, ok := fieldName(field)
+				if !ok {
+					continue
+				}
+				if field.Anonymous && !tagged {
+					ft := field.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, ft)
+						continue
+					}
+				}
+				fields = append(fields, structField{name: name, tagged: tagged, depth: depth, field: field})
+			}
+		}
+		current = next
+	}
+
+	byName := make(map[string][]structField)
+	names := []string{}
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	dominant := []structField{}
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			dominant = append(dominant, f)
+		}
+	}
+	return dominant
+}
+
+//dominantField returns the field that is used for a name shared by fields. fields are in
+// order of depth.
+func dominantField(fields []structField) (structField, bool) {
+	shallowest := []structField{}
+	for _, f := range fields {
+		if f.depth == fields[0].depth {
+			shallowest = append(shallowest, f)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	tagged := []structField{}
+	for _, f := range shallowest {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return structField{}, false
+}
+
+//fieldName returns the name of the property for a struct field, following the json tag
+// the way encoding/json does it, and reports if the name came from the tag. it reports
+// false if the field should be skipped.
+func fieldName(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		//unexported embedded structs are kept, because their exported fields are promoted.
+		if !field.Anonymous || ft.Kind() != reflect.Struct {
+			return "", false, false
+		}
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true, true
+	}
+	return field.Name, false, true
+}
+
+//applyTag parses the validapi tag of a field and adds the rules it describes to the property.
+func applyTag(prop Props, tag string) error {
+	switch p := prop.(type) {
+	case *Property:
+		required, rules, _, err := tagRules(tag, p.propType, nil)
+		if err == nil {
+			err = checkRules(p, rules)
+		}
+		if err != nil {
+			return err
+		}
+		p.AddRules(rules...)
+		if required {
+			p.Required()
+		}
+	case *ArrayProperty:
+		required, rules, itemRules, err := tagRules(tag, p.propType, p.item.propType)
+		if err == nil {
+			err = checkRules(p, rules)
+		}
+		if err == nil {
+			err = checkRules(p.item, itemRules)
+		}
+		if err != nil {
+			return err
+		}
+		p.AddRules(rules...).AddItemRules(itemRules...)
+		if required {
+			p.Required()
+		}
+	case *ObjectProperty:
+		required, rules, _, err := tagRules(tag, p.getType(), p.propType)
+		if err == nil {
+			err = checkRules(p, rules)
+		}
+		if err != nil {
+			return err
+		}
+		p.AddRules(rules...)
+		if required {
+			p.Required()
+		}
+	case *MapProperty:
+		required, _, _, err := tagRules(tag, p.propType, nil)
+		if err != nil {
+			return err
+		}
+		if required {
+			p.Required()
+		}
+	}
+	return nil
+}
+
+//checkRules reports the first rule that cannot be added to the property, so tag errors are
+// returned instead of panicking in AddRules.
+func checkRules(p Props, rules []Rule) error {
+	for _, rule := range rules {
+		if err := rule.rulevalidation(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//tagRules parses a validapi tag into the rules it describes for a property of Type t.
+// for arrays, min and max limit the number of items, and the other rules are returned as
+// item rules built for the item Type. objects and the items of slices of objects only
+// accept the required option. it reports if the tag marks the property as required.
+func tagRules(tag string, t, item Type) (bool, []Rule, []Rule, error) {
+	required := false
+	rules := []Rule{}
+	itemRules := []Rule{}
+	if tag == "" {
+		return required, rules, itemRules, nil
+	}
+
+	for _, opt := range strings.Split(tag, ",") {
+		key, val := opt, ""
+		if i := strings.Index(opt, "="); i >= 0 {
+			key, val = opt[:i], opt[i+1:]
+		}
+		if key == "required" {
+			required = true
+			continue
+		}
+		ruleType := t
+		if t == Array && key != "min" && key != "max" {
+			ruleType = item
+		}
+		if ruleType == Group {
+			return false, nil, nil, fmt.Errorf("option %q cannot be used with objects", key)
+		}
+
+		var rule Rule
+		var err error
+		switch key {
+		case "min", "max":
+			rule, err = limitRule(key, val, t)
+		case "format":
+			rule, err = NewFormatRule(val)
+		case "enum":
+			rule, err = tagEnumRule(val, ruleType)
 		default:
-			panic(fmt.Errorf("type of %v not supported", field.Type.String()))
+			err = fmt.Errorf("unknown option %q", key)
 		}
+		if err != nil {
+			return false, nil, nil, err
+		}
+		if ruleType != t {
+			itemRules = append(itemRules, rule)
+		} else {
+			rules = append(rules, rule)
+		}
+	}
+	return required, rules, itemRules, nil
+}
 
-		propGroup.AddProperties(prop)
+//limitRule builds the rule for a min or max option. numbers are limited by value, strings
+// by length and arrays by the number of items.
+func limitRule(key, val string, t Type) (Rule, error) {
+	if t == Array {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("%v must be an integer for slices. got %q", key, val)
+		}
+		if key == "min" {
+			return NewMinItemsRule(n), nil
+		}
+		return NewMaxItemsRule(n), nil
+	}
+	if t == String {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("%v must be an integer for strings. got %q", key, val)
+		}
+		if key == "min" {
+			return NewMinLengthRule(n), nil
+		}
+		return NewMaxLengthRule(n), nil
 	}
 
-	return propGroup
+	n, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%v must be a number. got %q", key, val)
+	}
+	if key == "min" {
+		return NewMinRule(n), nil
+	}
+	return NewMaxRule(n), nil
+}
+
+//tagEnumRule builds an EnumRule from members separated by '|'.
+func tagEnumRule(val string, t Type) (Rule, error) {
+	members := []interface{}{}
+	for _, m := range strings.Split(val, "|") {
+		switch {
+		case isNumeric(t):
+			members = append(members, json.Number(m))
+		case t == Boolean:
+			b, err := strconv.ParseBool(m)
+			if err != nil {
+				return nil, fmt.Errorf("enum member %q is not a bool", m)
+			}
+			members = append(members, b)
+		default:
+			members = append(members, m)
+		}
+	}
+	return NewEnumRule(members, t)
 }
@@ -2,13 +2,16 @@ package validapi
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type testStruct struct {
 	Name  string
 	Score float64
 	ID    int
+	Big   int64
 	Bool  bool
 }
 
@@ -22,6 +25,7 @@ func TestPropsFromType(t *testing.T) {
 		{"Name", String},
 		{"Score", Float},
 		{"ID", Int},
+		{"Big", Int64},
 		{"Bool", Boolean},
 	}
 
@@ -35,3 +39,303 @@ func TestPropsFromType(t *testing.T) {
 		}
 	}
 }
+
+type taggedStruct struct {
+	Name     string `json:"name" validapi:"required,min=3,max=50"`
+	Email    string `json:"email,omitempty" validapi:"format=email"`
+	Role     string `json:"role" validapi:"enum=admin|user"`
+	Age      int    `json:"age" validapi:"min=0,max=150,enum=18|21"`
+	Internal string `json:"-"`
+	NoTag    bool
+	hidden   string
+}
+
+func TestPropsFromTypeTags(t *testing.T) {
+	propGroup := PropsFromType(reflect.TypeOf(taggedStruct{}))
+
+	for _, name := range []string{"Internal", "hidden", "Name", "Email"} {
+		if _, ok := propGroup.properties[name]; ok {
+			t.Errorf("prop %v should not be in propertygroup", name)
+		}
+	}
+	if _, ok := propGroup.properties["NoTag"]; !ok {
+		t.Error("prop NoTag should be named after the field")
+	}
+	if !propGroup.properties["name"].isRequired() || propGroup.properties["email"].isRequired() {
+		t.Error("only name should be required")
+	}
+
+	testData := []struct {
+		name string
+		body map[string]interface{}
+		code string
+	}{
+		{"valid", map[string]interface{}{"name": "Jimbo", "email": "jimbo@example.com", "role": "admin", "age": 21.0}, ""},
+		{"required", map[string]interface{}{}, CodeRequired},
+		{"min length", map[string]interface{}{"name": "Jo"}, CodeMinLength},
+		{"max length", map[string]interface{}{"name": strings.Repeat("a", 51)}, CodeMaxLength},
+		{"format", map[string]interface{}{"name": "Jimbo", "email": "jimbo"}, "format_email"},
+		{"string enum", map[string]interface{}{"name": "Jimbo", "role": "owner"}, CodeEnum},
+		{"maximum", map[string]interface{}{"name": "Jimbo", "age": 200.0}, CodeMaximum},
+		{"number enum", map[string]interface{}{"name": "Jimbo", "age": 20.0}, CodeEnum},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			err := propGroup.Validate(i.body)
+			if i.code == "" {
+				if err != nil {
+					t.Errorf("wanted nil got %v", err.Error())
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || errs[0].Code != i.code {
+				t.Errorf("wanted code %v got %v", i.code, err)
+			}
+		})
+	}
+}
+
+func TestPropsFromTypeInvalidTags(t *testing.T) {
+	testData := []struct {
+		name string
+		typ  interface{}
+	}{
+		{"unknown option", struct {
+			Name string `validapi:"nope"`
+		}{}},
+		{"bad min", struct {
+			Name string `validapi:"min=a"`
+		}{}},
+		{"unknown format", struct {
+			Name string `validapi:"format=nope"`
+		}{}},
+		{"bad enum", struct {
+			ID int `validapi:"enum=1|a"`
+		}{}},
+		{"format on int", struct {
+			N int `validapi:"format=email"`
+		}{}},
+		{"format on bool items", struct {
+			Flags []bool `validapi:"format=email"`
+		}{}},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "invalid validapi tag") {
+					t.Errorf("wanted a tag error got %v", r)
+				}
+			}()
+			PropsFromType(reflect.TypeOf(i.typ))
+		})
+	}
+}
+
+type Base struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+type audit struct {
+	Updated string `json:"updated"`
+	By      string `json:"by"`
+}
+
+type address struct {
+	City string `json:"city" validapi:"required"`
+}
+
+type treeNode struct {
+	Name     string     `json:"name"`
+	Children []treeNode `json:"children"`
+	Parent   *treeNode  `json:"parent"`
+}
+
+type modelStruct struct {
+	Base
+	*audit
+	Address   address            `json:"address" validapi:"required"`
+	Addresses []address          `json:"addresses" validapi:"max=2"`
+	Tags      []string           `json:"tags" validapi:"min=1,max=2,enum=a|b"`
+	Nick      *string            `json:"nick"`
+	Scores    map[string]float64 `json:"scores"`
+	Born      time.Time          `json:"born"`
+	Avatar    []byte             `json:"avatar"`
+	Tree      treeNode           `json:"tree"`
+}
+
+func TestPropsFromTypeComposite(t *testing.T) {
+	propGroup := PropsFromType(reflect.TypeOf(modelStruct{}))
+
+	for _, name := range []string{"id", "created", "updated", "by", "address", "addresses", "tags", "nick", "scores", "born", "avatar", "tree"} {
+		if _, ok := propGroup.properties[name]; !ok {
+			t.Errorf("prop %v should be in propertygroup but was not", name)
+		}
+	}
+	if _, ok := propGroup.properties["Base"]; ok {
+		t.Error("embedded struct should be flattened")
+	}
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"id":        1.0,
+			"created":   "now",
+			"address":   map[string]interface{}{"city": "Boston"},
+			"addresses": []interface{}{map[string]interface{}{"city": "Boston"}},
+			"tags":      []interface{}{"a"},
+			"nick":      nil,
+			"scores":    map[string]interface{}{"math": 1.5},
+			"born":      "2006-01-02T15:04:05Z",
+			"avatar":    "aGVsbG8=",
+			"tree": map[string]interface{}{
+				"name":     "root",
+				"children": []interface{}{map[string]interface{}{"name": "leaf", "parent": nil}},
+			},
+		}
+	}
+
+	testData := []struct {
+		name  string
+		key   string
+		value interface{}
+		path  string
+		code  string
+	}{
+		{"valid", "", nil, "", ""},
+		{"nested required", "address", map[string]interface{}{}, "/address/city", CodeRequired},
+		{"slice of objects max items", "addresses", []interface{}{map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}}, "/addresses", CodeMaxItems},
+		{"slice of objects", "addresses", []interface{}{map[string]interface{}{"city": 1.0}}, "/addresses/0/city", CodeTypeMismatch},
+		{"min items", "tags", []interface{}{}, "/tags", CodeMinItems},
+		{"item enum", "tags", []interface{}{"c"}, "/tags/0", CodeEnum},
+		{"pointer", "nick", "jimbo", "", ""},
+		{"not nullable", "address", nil, "/address", CodeTypeMismatch},
+		{"map value", "scores", map[string]interface{}{"math": "A"}, "/scores/math", CodeTypeMismatch},
+		{"time", "born", "yesterday", "/born", "format_date_time"},
+		{"bytes", "avatar", "not base64!", "/avatar", "format_base64"},
+		{"recursive", "tree", map[string]interface{}{
+			"children": []interface{}{map[string]interface{}{"parent": map[string]interface{}{"name": 1.0}}},
+		}, "/tree/children/0/parent/name", CodeTypeMismatch},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			body := valid()
+			if i.key != "" {
+				body[i.key] = i.value
+			}
+			err := propGroup.Validate(body)
+			if i.code == "" {
+				if err != nil {
+					t.Errorf("wanted nil got %v", err.Error())
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || errs[0].Path != i.path || errs[0].Code != i.code {
+				t.Errorf("wanted %v %v got %v", i.path, i.code, err)
+			}
+		})
+	}
+}
+
+func TestPropsFromTypeEmbeddedConflicts(t *testing.T) {
+	type A struct {
+		Name string
+		Both string
+	}
+	type B struct {
+		Name string `json:"Name"`
+		Both string
+	}
+	type outer struct {
+		A
+		B
+		ID int
+	}
+	propGroup := PropsFromType(reflect.TypeOf(outer{}))
+
+	if _, ok := propGroup.properties["Both"]; ok {
+		t.Error("conflicting untagged fields should be dropped")
+	}
+	if _, ok := propGroup.properties["Name"]; !ok {
+		t.Error("tagged field should win a conflict")
+	}
+	if len(propGroup.properties) != 2 {
+		t.Errorf("wanted 2 props got %v", len(propGroup.properties))
+	}
+}
+
+func TestPropsFromTypeUnsupported(t *testing.T) {
+	testData := []struct {
+		name string
+		typ  interface{}
+	}{
+		{"interface", struct{ Value interface{} }{}},
+		{"nested slices", struct{ Matrix [][]int }{}},
+		{"int map keys", struct{ Counts map[int]int }{}},
+		{"object rules", struct {
+			Address address `validapi:"min=1"`
+		}{}},
+		{"object item rules", struct {
+			Addresses []address `validapi:"enum=a"`
+		}{}},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("wanted panic got nil")
+				}
+			}()
+			PropsFromType(reflect.TypeOf(i.typ))
+		})
+	}
+}
+
+func TestPropsFromTypeIntegerKinds(t *testing.T) {
+	type sized struct {
+		Small int8   `json:"small"`
+		Short int16  `json:"short"`
+		Byte  uint8  `json:"byte"`
+		Port  uint16 `json:"port"`
+		Count uint32 `json:"count"`
+		ID    uint64 `json:"id"`
+	}
+	propGroup := PropsFromType(reflect.TypeOf(sized{}))
+
+	types := map[string]Type{"small": Int8, "short": Int16, "byte": Uint8, "port": Uint16, "count": Uint32, "id": Uint64}
+	for name, typ := range types {
+		if got := propGroup.properties[name].getType(); got != typ {
+			t.Errorf("%v: want %v got %v", name, typ, got)
+		}
+	}
+
+	testData := []struct {
+		body string
+		path string
+	}{
+		{`{"small": -128, "short": 32767, "byte": 255, "port": 65535, "count": 4294967295, "id": 18446744073709551615}`, ""},
+		{`{"small": 128}`, "/small"},
+		{`{"byte": -1}`, "/byte"},
+		{`{"id": 18446744073709551616}`, "/id"},
+	}
+
+	for _, i := range testData {
+		_, err := propGroup.ValidateJSON([]byte(i.body))
+		if i.path == "" {
+			if err != nil {
+				t.Errorf("%v: wanted nil got %v", i.body, err.Error())
+			}
+			continue
+		}
+		errs, ok := err.(ValidationErrors)
+		if !ok || errs[0].Path != i.path || errs[0].Code != CodeOutOfRange {
+			t.Errorf("%v: wanted out of range at %v got %v", i.body, i.path, err)
+		}
+	}
+}
@@ -1,14 +1,187 @@
 package validapi
 
 import (
+	"errors"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 type (
 	//ValidAPI ...
 	ValidAPI struct {
-		tree            Router
+		Router
 		NotFoundHandler func(http.ResponseWriter, *http.Request)
-		CORS            bool
+		//MethodNotAllowedHandler is called when the path matches a route, but no handler is
+		// registered for the request method. the Allow header is set before it is called.
+		MethodNotAllowedHandler func(http.ResponseWriter, *http.Request)
+		//DisableAutoOptions turns off the automatic answer to OPTIONS requests that have no
+		// handler, which responds with the allowed methods.
+		DisableAutoOptions bool
+		//DisableAutoHead turns off answering HEAD requests that have no handler with the GET
+		// handler.
+		DisableAutoHead bool
+		//ErrorRenderer writes the response for requests that fail validation and the default
+		// not found and method not allowed responses. ProblemRenderer is used if it is nil.
+		ErrorRenderer ErrorRenderer
 	}
 )
+
+//New creates a ValidAPI with an empty routing tree. automatic OPTIONS and HEAD responses are
+// on by default, for ValidAPI literals as well.
+func New() *ValidAPI {
+	return &ValidAPI{}
+}
+
+//ServeHTTP finds the handler registered for the request path and method, runs it through
+// the middleware collected along the way and validates the request body against the
+// PropertyGroup of the route before calling it. CORS requests are answered using the policy
+// of the innermost Router on the branch that serves the request, or the policy of the api
+// when no route matches. preflight requests use the branch of the requested method.
+func (api *ValidAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	uri := splitPath(r.URL.Path)
+	if isPreflight(r) {
+		_, l, m := api.lookup(r.Header.Get("Access-Control-Request-Method"), uri)
+		if l != nil && m.policy != nil {
+			m.policy.handlePreflight(w, r, api.allowedMethods(uri))
+			return
+		}
+	}
+
+	mw, l, m := api.lookup(r.Method, uri)
+	if r.Header.Get("Origin") != "" && !isPreflight(r) {
+		policy := m.policy
+		if l == nil {
+			policy = api.CORS
+		}
+		if policy != nil {
+			policy.handleActual(w, r)
+		}
+	}
+	if l == nil {
+		api.noMatch(w, r, uri)
+		return
+	}
+	if len(m.params) > 0 {
+		r = withParams(r, m.params)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		l.serve(w, r, api.renderError)
+	}
+	for i := len(l.middleware) - 1; i >= 0; i-- {
+		handler = l.middleware[i](handler)
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = (*mw[i])(handler)
+	}
+	handler(w, r)
+}
+
+//lookup finds the leaf for the method and path, answering HEAD requests with the GET handler
+// unless DisableAutoHead is set.
+func (api *ValidAPI) lookup(method string, uri []string) ([]*Middleware, *leaf, match) {
+	var m match
+	mw, l := api.find(method, uri, nil, &m)
+	if l == nil && method == http.MethodHead && !api.DisableAutoHead {
+		m = match{}
+		mw, l = api.find(http.MethodGet, uri, nil, &m)
+	}
+	return mw, l, m
+}
+
+//noMatch answers requests that did not find a handler. if the path matches a route with
+// other methods, it responds with the allowed methods instead of calling NotFoundHandler.
+func (api *ValidAPI) noMatch(w http.ResponseWriter, r *http.Request, uri []string) {
+	allow := api.allowedMethods(uri)
+	if len(allow) == 0 {
+		if api.NotFoundHandler != nil {
+			api.NotFoundHandler(w, r)
+			return
+		}
+		api.renderError(w, r, http.StatusNotFound, errors.New(http.StatusText(http.StatusNotFound)))
+		return
+	}
+
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	if r.Method == http.MethodOptions && !api.DisableAutoOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if api.MethodNotAllowedHandler != nil {
+		api.MethodNotAllowedHandler(w, r)
+		return
+	}
+	api.renderError(w, r, http.StatusMethodNotAllowed, errors.New(http.StatusText(http.StatusMethodNotAllowed)))
+}
+
+//allowedMethods returns the sorted list of methods the path can be requested with, including
+// the automatic HEAD and OPTIONS responses. returns nil if the path does not match any route.
+func (api *ValidAPI) allowedMethods(uri []string) []string {
+	methods := make(map[string]struct{})
+	api.allowed(uri, methods)
+	if len(methods) == 0 {
+		return nil
+	}
+	if _, ok := methods[http.MethodGet]; ok && !api.DisableAutoHead {
+		methods[http.MethodHead] = struct{}{}
+	}
+	if !api.DisableAutoOptions {
+		methods[http.MethodOptions] = struct{}{}
+	}
+
+	allow := make([]string, 0, len(methods))
+	for method := range methods {
+		allow = append(allow, method)
+	}
+	sort.Strings(allow)
+	return allow
+}
+
+//serve validates the typed path parameters and the request body against the leaf's
+// PropertyGroup, if it has one, and calls the handler. the decoded body is stored in the
+// request context, and the body is restored so the handler can decode it again. failures
+// are written with the ErrorRenderer.
+func (l *leaf) serve(w http.ResponseWriter, r *http.Request, render ErrorRenderer) {
+	if len(l.params) > 0 {
+		v := newValidator(0)
+		ps := ParamsFromRequest(r)
+		for i := range ps {
+			prop, ok := l.params[ps[i].Key]
+			if !ok {
+				continue
+			}
+			path := joinPath("", ps[i].Key)
+			val, err := convertParam(prop, ps[i].Value)
+			if err != nil {
+				v.add(&ValidationError{Path: path, Code: CodeTypeMismatch, Message: err.Error(), Expected: prop.propType.String(), Actual: ps[i].Value})
+				continue
+			}
+			prop.collect(path, val, v)
+			ps[i].value = val
+		}
+		if err := v.err(); err != nil {
+			render(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if l.group != nil {
+		var err error
+		if r, err = validateRequestBody(r, l.group); err != nil {
+			render(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+	l.handler(w, r)
+}
+
+//renderError writes the error with the ErrorRenderer of the api, or ProblemRenderer if it
+// is not set.
+func (api *ValidAPI) renderError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if api.ErrorRenderer != nil {
+		api.ErrorRenderer(w, r, status, err)
+		return
+	}
+	ProblemRenderer(w, r, status, err)
+}
@@ -0,0 +1,136 @@
+package validapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHTTP(t *testing.T) {
+	api := New()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	group := NewPropertyGroup().AddProperties(NewProperty("Name", String))
+	api.Post("/users", ok).Body(group)
+
+	testData := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"valid body", http.MethodPost, "/users", `{"Name": "Jimbo"}`, http.StatusOK},
+		{"invalid body", http.MethodPost, "/users", `{"Name": 12}`, http.StatusBadRequest},
+		{"not json", http.MethodPost, "/users", `Name`, http.StatusBadRequest},
+		{"trailing data", http.MethodPost, "/users", `{"Name": "Jimbo"} {"junk": true}`, http.StatusBadRequest},
+		{"not found", http.MethodPost, "/groups", `{}`, http.StatusNotFound},
+	}
+
+	for _, i := range testData {
+		t.Run(i.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, httptest.NewRequest(i.method, i.path, strings.NewReader(i.body)))
+			if rec.Code != i.want {
+				t.Errorf("got status %v want %v", rec.Code, i.want)
+			}
+		})
+	}
+}
+
+func TestServeHTTPMiddlewareOrder(t *testing.T) {
+	api := New()
+	order := ""
+	tag := func(s string) Middleware {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				order += s
+				next(w, r)
+			}
+		}
+	}
+	api.middleware = []Middleware{tag("a"), tag("b")}
+	api.Get("/", func(w http.ResponseWriter, r *http.Request) { order += "h" })
+
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if order != "abh" {
+		t.Errorf("got order %v want abh", order)
+	}
+}
+
+func TestNotFoundHandler(t *testing.T) {
+	api := New()
+	api.NotFoundHandler = func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }
+
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("got status %v want %v", rec.Code, http.StatusTeapot)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	api := New()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	api.Get("/users", ok)
+	api.Post("/users", ok)
+
+	testData := []struct {
+		method string
+		status int
+		allow  string
+	}{
+		{http.MethodDelete, http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST"},
+		{http.MethodOptions, http.StatusNoContent, "GET, HEAD, OPTIONS, POST"},
+		{http.MethodHead, http.StatusOK, ""},
+	}
+
+	for _, i := range testData {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(i.method, "/users", nil))
+		if rec.Code != i.status {
+			t.Errorf("%v: got status %v want %v", i.method, rec.Code, i.status)
+		}
+		if got := rec.Header().Get("Allow"); got != i.allow {
+			t.Errorf("%v: got Allow %q want %q", i.method, got, i.allow)
+		}
+	}
+}
+
+func TestMethodNotAllowedHandler(t *testing.T) {
+	api := New()
+	api.DisableAutoOptions = true
+	api.DisableAutoHead = true
+	api.MethodNotAllowedHandler = func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }
+	api.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, method := range []string{http.MethodOptions, http.MethodHead, http.MethodPut} {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(method, "/users", nil))
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("%v: got status %v want %v", method, rec.Code, http.StatusTeapot)
+		}
+		if got := rec.Header().Get("Allow"); got != "GET" {
+			t.Errorf("%v: got Allow %q want GET", method, got)
+		}
+	}
+}
+
+func TestAutoResponsesZeroValue(t *testing.T) {
+	api := &ValidAPI{}
+	api.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, i := range []struct {
+		method string
+		status int
+	}{
+		{http.MethodHead, http.StatusOK},
+		{http.MethodOptions, http.StatusNoContent},
+	} {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(i.method, "/users", nil))
+		if rec.Code != i.status {
+			t.Errorf("%v: got status %v want %v", i.method, rec.Code, i.status)
+		}
+	}
+}